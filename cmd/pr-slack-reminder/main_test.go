@@ -3,6 +3,7 @@ package main_test
 import (
 	"cmp"
 	"errors"
+	"fmt"
 	"slices"
 	"strconv"
 	"strings"
@@ -24,6 +25,16 @@ type GetTestPROptions struct {
 	AuthorName  string
 	Labels      []string
 	AgeHours    float32
+	Draft       bool
+	BaseBranch  string
+	HeadBranch  string
+	// Reported as additions of the PR
+	LinesChanged int
+	HeadSHA      string
+	Mergeable    *bool
+	// Logins of the users and slugs of the teams whose review is requested
+	RequestedReviewers []string
+	RequestedTeams     []string
 }
 
 var now = time.Now()
@@ -43,6 +54,14 @@ func getTestPR(options GetTestPROptions) *github.PullRequest {
 			Name: &label,
 		})
 	}
+	var requestedReviewers []*github.User
+	for _, login := range options.RequestedReviewers {
+		requestedReviewers = append(requestedReviewers, &github.User{Login: github.Ptr(login)})
+	}
+	var requestedTeams []*github.Team
+	for _, slug := range options.RequestedTeams {
+		requestedTeams = append(requestedTeams, &github.Team{Slug: github.Ptr(slug)})
+	}
 	prTime := now.Add(-time.Duration(
 		cmp.Or(options.AgeHours, float32(5.0))) * time.Hour,
 	)
@@ -56,6 +75,17 @@ func getTestPR(options GetTestPROptions) *github.PullRequest {
 		},
 		Labels:    githubLabels,
 		CreatedAt: &github.Timestamp{Time: prTime},
+		Draft:     &options.Draft,
+		Base:      &github.PullRequestBranch{Ref: github.Ptr(cmp.Or(options.BaseBranch, "main"))},
+		Head: &github.PullRequestBranch{
+			Ref: github.Ptr(cmp.Or(options.HeadBranch, "feature/"+title)),
+			SHA: &options.HeadSHA,
+		},
+		Additions:          &options.LinesChanged,
+		Mergeable:          options.Mergeable,
+		Deletions:          github.Ptr(0),
+		RequestedReviewers: requestedReviewers,
+		RequestedTeams:     requestedTeams,
 	}
 }
 
@@ -147,22 +177,53 @@ func filterPRsByNumbers(
 	return filteredPRs
 }
 
+// Returns the numbers of the PRs in the order they are listed in the message (by their titles).
+func getListedPRNumbers(blocks mockslackclient.BlocksWrapper, prs []*github.PullRequest) []int {
+	numbers := []int{}
+	for _, prList := range blocks.GetPRLists() {
+		for _, item := range prList.PRListItems {
+			for _, pr := range prs {
+				if strings.Contains(item, pr.GetTitle()) {
+					numbers = append(numbers, pr.GetNumber())
+				}
+			}
+		}
+	}
+	return numbers
+}
+
 func TestScenarios(t *testing.T) {
 	testCases := []struct {
-		name               string
-		config             testhelpers.TestConfig
-		configOverrides    *map[string]any
-		fetchPRsStatus     int
-		fetchPRsError      error
-		prs                []*github.PullRequest
-		prsByRepo          map[string][]*github.PullRequest
-		reviewsByPRNumber  map[int][]*github.PullRequestReview
-		foundSlackChannels []*mockslackclient.SlackChannel
-		findChannelError   error
-		sendMessageError   error
-		expectedErrorMsg   string
-		expectedPRNumbers  []int
-		expectedSummary    string
+		name                     string
+		config                   testhelpers.TestConfig
+		configOverrides          *map[string]any
+		fetchPRsStatus           int
+		fetchPRsError            error
+		fetchPRsErrorByRepo      map[string]error
+		prs                      []*github.PullRequest
+		prsByRepo                map[string][]*github.PullRequest
+		reviewsByPRNumber        map[int][]*github.PullRequestReview
+		checkRunsByHeadSHA       map[string][]*github.CheckRun
+		timelineEventsByPRNumber map[int][]*github.Timeline
+		foundSlackChannels       []*mockslackclient.SlackChannel
+		findChannelError         error
+		sendMessageError         error
+		expectedErrorMsg         string
+		expectedPRNumbers        []int
+		expectedSummary          string
+		expectedFooterText       string
+		// PR list headings that are expected to be included in the message (in this order),
+		// defaults to the main list heading
+		expectedHeadings []string
+		// PRs (by number) that are expected to be listed under the given headings
+		expectedPRNumbersByHeading map[string][]int
+		// The number of PR list items in the message, defaults to the number of expected PRs
+		// (PRs may be listed more than once if they are grouped)
+		expectedListItemCount int
+		// Mentions (in Slack mrkdwn format) expected at the top of the PR lists
+		expectedMentions []string
+		// PRs (by number) in the order they are expected to be listed in the message
+		expectedPROrder []int
 	}{
 		{
 			name:   "unset required inputs",
@@ -190,6 +251,12 @@ func TestScenarios(t *testing.T) {
 			},
 			expectedErrorMsg: "configuration error: if old-pr-threshold-hours is set, old-prs-list-heading must also be set",
 		},
+		{
+			name:             "invalid reviewer display input",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputReviewerDisplay: "shout"},
+			expectedErrorMsg: "configuration error: invalid value for input reviewer-display: shout (must be one of [mention name none])",
+		},
 		{
 			name:             "invalid repository input 1",
 			config:           testhelpers.GetDefaultConfigMinimal(),
@@ -245,6 +312,45 @@ func TestScenarios(t *testing.T) {
 			fetchPRsError:    errors.New("unable to fetch PRs"),
 			expectedErrorMsg: "error fetching pull requests from test-org/test-repo: unable to fetch PRs",
 		},
+		{
+			name:   "unreadable repository fails the run",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGithubRepositories: "some-org/repo1; some-org/archived-repo",
+			},
+			prsByRepo: map[string][]*github.PullRequest{
+				"repo1": {getTestPR(GetTestPROptions{Number: 1})},
+			},
+			fetchPRsErrorByRepo: map[string]error{"archived-repo": errors.New("not found")},
+			expectedErrorMsg:    "repository some-org/archived-repo not found - check the repository name and permissions",
+		},
+		{
+			name:   "unreadable repository reported in footer",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGithubRepositories:         "some-org/repo1; some-org/archived-repo",
+				config.InputContinueOnRepositoryErrors: "true",
+			},
+			prsByRepo: map[string][]*github.PullRequest{
+				"repo1": {getTestPR(GetTestPROptions{Number: 1})},
+			},
+			fetchPRsErrorByRepo: map[string]error{"archived-repo": errors.New("not found")},
+			expectedPRNumbers:   []int{1},
+			expectedSummary:     "1 open PR is waiting for attention 👀",
+			expectedFooterText:  "Unable to fetch PRs from: some-org/archived-repo",
+		},
+		{
+			name:   "unreadable repository reported without PRs or no-prs-message",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGithubRepositories:         "some-org/repo1; some-org/archived-repo",
+				config.InputContinueOnRepositoryErrors: "true",
+			},
+			prsByRepo:           map[string][]*github.PullRequest{"repo1": {}},
+			fetchPRsErrorByRepo: map[string]error{"archived-repo": errors.New("not found")},
+			expectedSummary:     "No open PRs found in the repositories that could be read",
+			expectedFooterText:  "Unable to fetch PRs from: some-org/archived-repo",
+		},
 		{
 			name:   "no Slack channel found",
 			config: testhelpers.GetDefaultConfigMinimal(),
@@ -315,6 +421,580 @@ func TestScenarios(t *testing.T) {
 			prs:             getTestPRs(GetTestPRsOptions{AuthorUser: "lilo"}).PRs,
 			expectedSummary: "", // no message should be sent
 		},
+		{
+			name:             "invalid global filters input: unknown drafts option",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputGlobalFilters: "{\"drafts\": \"hide\"}"},
+			expectedErrorMsg: "configuration error: error reading input filters: invalid filters: {\"drafts\": \"hide\"}, error: drafts filter must be one of include, exclude or only",
+		},
+		{
+			name:            "draft PRs excluded",
+			config:          testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{config.InputGlobalFilters: "{\"drafts\": \"exclude\"}"},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, Draft: true}),
+				getTestPR(GetTestPROptions{Number: 2}),
+			},
+			expectedPRNumbers: []int{2},
+			expectedSummary:   "1 open PR is waiting for attention 👀",
+		},
+		{
+			name:            "only draft PRs included",
+			config:          testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{config.InputGlobalFilters: "{\"drafts\": \"only\"}"},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, Draft: true}),
+				getTestPR(GetTestPROptions{Number: 2}),
+			},
+			expectedPRNumbers: []int{1},
+			expectedSummary:   "1 open PR is waiting for attention 👀",
+		},
+		{
+			name:            "draft PRs listed separately",
+			config:          testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{config.InputDraftPRsListHeading: "Drafts (<pr_count>)"},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, Draft: true}),
+				getTestPR(GetTestPROptions{Number: 2, Draft: true}),
+				getTestPR(GetTestPROptions{Number: 3}),
+			},
+			expectedPRNumbers: []int{1, 2, 3},
+			expectedSummary:   "3 open PRs are waiting for attention 👀",
+			expectedHeadings:  []string{"There are 3 open PRs 🚀", "Drafts (2)"},
+		},
+		{
+			name:   "PRs filtered by base and head branch patterns",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGlobalFilters: "{\"base-branches\": [\"main\", \"release/*\"], \"head-branches-ignore\": [\"dependabot/*\"]}",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, BaseBranch: "main"}),
+				getTestPR(GetTestPROptions{Number: 2, BaseBranch: "release/2.0"}),
+				getTestPR(GetTestPROptions{Number: 3, BaseBranch: "develop"}),
+				getTestPR(GetTestPROptions{Number: 4, HeadBranch: "dependabot/npm_and_yarn/lodash-4.17.21"}),
+			},
+			expectedPRNumbers: []int{1, 2},
+			expectedSummary:   "2 open PRs are waiting for attention 👀",
+		},
+		{
+			name:             "invalid global filters input: conflicting head-branches and head-branches-ignore",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputGlobalFilters: "{\"head-branches\": [\"fix/*\"], \"head-branches-ignore\": [\"fix/*\"]}"},
+			expectedErrorMsg: "error: head-branches filter cannot contain patterns that are in head-branches-ignore filter",
+		},
+		{
+			name:   "PRs filtered by label and author patterns",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGlobalFilters: "{\"labels\": [\"area/*\", \"re:^team:payments-.+$\"], \"authors-ignore\": [\"*[bot]\"]}",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, Labels: []string{"area/frontend"}}),
+				getTestPR(GetTestPROptions{Number: 2, Labels: []string{"team:payments-core"}}),
+				getTestPR(GetTestPROptions{Number: 3, Labels: []string{"team:search"}}),
+				getTestPR(GetTestPROptions{Number: 4, Labels: []string{"area/backend"}, AuthorLogin: "renovate[bot]"}),
+			},
+			expectedPRNumbers: []int{1, 2},
+			expectedSummary:   "2 open PRs are waiting for attention 👀",
+		},
+		{
+			name:             "invalid global filters input: invalid regular expression",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputGlobalFilters: "{\"labels\": [\"re:team:(payments\"]}"},
+			expectedErrorMsg: "{\"labels\": [\"re:team:(payments\"]}: invalid pattern \"re:team:(payments\": error parsing regexp: missing closing ): `team:(payments`",
+		},
+		{
+			name:             "invalid global filters input: empty branch pattern",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputGlobalFilters: "{\"base-branches-ignore\": [\"\"]}"},
+			expectedErrorMsg: "{\"base-branches-ignore\": [\"\"]}: invalid pattern \"\": pattern cannot be empty",
+		},
+		{
+			name:   "PRs filtered by expression",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGlobalFilters: "{\"expression\": \"'urgent' in labels or (author in ['alice', 'bob'] and not draft)\"}",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, Labels: []string{"urgent"}, Draft: true}),
+				getTestPR(GetTestPROptions{Number: 2, AuthorLogin: "alice"}),
+				getTestPR(GetTestPROptions{Number: 3, AuthorLogin: "bob", Draft: true}),
+				getTestPR(GetTestPROptions{Number: 4, AuthorLogin: "carol"}),
+			},
+			expectedPRNumbers: []int{1, 2},
+			expectedSummary:   "2 open PRs are waiting for attention 👀",
+		},
+		{
+			name:             "invalid global filters input: invalid expression",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputGlobalFilters: "{\"expression\": \"author == 1\"}"},
+			expectedErrorMsg: "error: invalid expression: cannot compare string with number using \"==\" at position 8",
+		},
+		{
+			name:   "PRs filtered by size",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGlobalFilters: "{\"min-size\": 10, \"max-size\": 500}",
+				config.InputSizeBadges:    true,
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, LinesChanged: 5}),
+				getTestPR(GetTestPROptions{Number: 2, LinesChanged: 50}),
+				getTestPR(GetTestPROptions{Number: 3, LinesChanged: 500}),
+				getTestPR(GetTestPROptions{Number: 4, LinesChanged: 5000}),
+			},
+			expectedPRNumbers: []int{2, 3},
+			expectedSummary:   "2 open PRs are waiting for attention 👀",
+		},
+		{
+			name:             "invalid global filters input: min-size greater than max-size",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputGlobalFilters: "{\"min-size\": 100, \"max-size\": 10}"},
+			expectedErrorMsg: "error: min-size filter cannot be greater than max-size filter",
+		},
+		{
+			name:   "invalid size badge thresholds input",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputSizeBadges:          true,
+				config.InputSizeBadgeThresholds: "S: 100; M: 50",
+			},
+			expectedErrorMsg: "configuration error: size thresholds in input size-badge-thresholds must be in ascending order (got S: 100, M: 50)",
+		},
+		{
+			name:   "PRs filtered by CI status",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGlobalFilters: "{\"ci\": \"passing\"}",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, HeadSHA: "sha1"}),
+				getTestPR(GetTestPROptions{Number: 2, HeadSHA: "sha2"}),
+				getTestPR(GetTestPROptions{Number: 3, HeadSHA: "sha3"}),
+				getTestPR(GetTestPROptions{Number: 4}), // no CI
+			},
+			checkRunsByHeadSHA: map[string][]*github.CheckRun{
+				"sha1": {
+					{Status: github.Ptr("completed"), Conclusion: github.Ptr("success")},
+					{Status: github.Ptr("completed"), Conclusion: github.Ptr("skipped")},
+				},
+				"sha2": {
+					{Status: github.Ptr("completed"), Conclusion: github.Ptr("success")},
+					{Status: github.Ptr("completed"), Conclusion: github.Ptr("failure")},
+				},
+				"sha3": {{Status: github.Ptr("in_progress")}},
+			},
+			expectedPRNumbers: []int{1},
+			expectedSummary:   "1 open PR is waiting for attention 👀",
+		},
+		{
+			name:             "invalid global filters input: invalid ci filter",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputGlobalFilters: "{\"ci\": \"green\"}"},
+			expectedErrorMsg: "error: ci filter must be one of passing, failing or pending",
+		},
+		{
+			name:   "PRs that are ready to merge listed first",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputReadyToMergeListHeading: "Ready to merge (<pr_count>) 🚢",
+				config.InputReadyToMergeApprovals:   1,
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, HeadSHA: "sha1", Mergeable: github.Ptr(true)}),
+				getTestPR(GetTestPROptions{Number: 2, HeadSHA: "sha2", Mergeable: github.Ptr(false)}), // conflicts
+				getTestPR(GetTestPROptions{Number: 3, HeadSHA: "sha3", Mergeable: github.Ptr(true)}),  // CI failing
+				getTestPR(GetTestPROptions{Number: 4, HeadSHA: "sha1", Mergeable: github.Ptr(true)}),  // not approved
+				getTestPR(GetTestPROptions{Number: 5, HeadSHA: "sha1", Mergeable: github.Ptr(true)}),  // approval is stale
+				getTestPR(GetTestPROptions{Number: 6, HeadSHA: "sha4", Mergeable: github.Ptr(true)}),  // no CI
+			},
+			reviewsByPRNumber: map[int][]*github.PullRequestReview{
+				1: {{User: &github.User{Login: github.Ptr("alice")}, State: github.Ptr("APPROVED")}},
+				2: {{User: &github.User{Login: github.Ptr("alice")}, State: github.Ptr("APPROVED")}},
+				3: {{User: &github.User{Login: github.Ptr("alice")}, State: github.Ptr("APPROVED")}},
+				5: {{User: &github.User{Login: github.Ptr("alice")}, State: github.Ptr("APPROVED"), CommitID: github.Ptr("old-sha")}},
+				6: {{User: &github.User{Login: github.Ptr("alice")}, State: github.Ptr("APPROVED")}},
+			},
+			checkRunsByHeadSHA: map[string][]*github.CheckRun{
+				"sha1": {{Status: github.Ptr("completed"), Conclusion: github.Ptr("success")}},
+				"sha2": {{Status: github.Ptr("completed"), Conclusion: github.Ptr("success")}},
+				"sha3": {{Status: github.Ptr("completed"), Conclusion: github.Ptr("failure")}},
+			},
+			expectedPRNumbers: []int{1, 2, 3, 4, 5, 6},
+			expectedSummary:   "6 open PRs are waiting for attention 👀",
+			expectedHeadings:  []string{"Ready to merge (2) 🚢", "There are 6 open PRs 🚀"},
+			expectedPRNumbersByHeading: map[string][]int{
+				"Ready to merge (2) 🚢":   {1, 6},
+				"There are 6 open PRs 🚀": {2, 3, 4, 5},
+			},
+		},
+		{
+			name:   "PRs with merge conflicts listed separately",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputNeedsRebaseListHeading: "Needs rebase (<pr_count>) 🔧",
+				config.InputDraftPRsListHeading:    "Drafts (<pr_count>)",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, Mergeable: github.Ptr(true)}),
+				getTestPR(GetTestPROptions{Number: 2, Mergeable: github.Ptr(false)}),
+				getTestPR(GetTestPROptions{Number: 3, Mergeable: github.Ptr(false), Draft: true}),
+			},
+			expectedPRNumbers: []int{1, 2, 3},
+			expectedSummary:   "3 open PRs are waiting for attention 👀",
+			expectedHeadings:  []string{"There are 3 open PRs 🚀", "Needs rebase (1) 🔧", "Drafts (1)"},
+			expectedPRNumbersByHeading: map[string][]int{
+				"There are 3 open PRs 🚀": {1},
+				"Needs rebase (1) 🔧":     {2},
+				"Drafts (1)":             {3},
+			},
+		},
+		{
+			name:   "PRs listed in age categories",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputAgeCategories:   "72: Older than 3 days (<pr_count>); 0: Less than a day old (<pr_count>); 24: 1-3 days old (<pr_count>)",
+				config.InputMainListHeading: "",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, AgeHours: 2}),
+				getTestPR(GetTestPROptions{Number: 2, AgeHours: 30}),
+				getTestPR(GetTestPROptions{Number: 3, AgeHours: 50}),
+				getTestPR(GetTestPROptions{Number: 4, AgeHours: 100}),
+			},
+			expectedPRNumbers: []int{1, 2, 3, 4},
+			expectedSummary:   "4 open PRs are waiting for attention 👀",
+			expectedHeadings:  []string{"Less than a day old (1)", "1-3 days old (2)", "Older than 3 days (1)"},
+			expectedPRNumbersByHeading: map[string][]int{
+				"Less than a day old (1)": {1},
+				"1-3 days old (2)":        {2, 3},
+				"Older than 3 days (1)":   {4},
+			},
+		},
+		{
+			name:   "PR age measured from when marked ready for review",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputAgeCategories: "0: New PRs (<pr_count>); 48: Old PRs (<pr_count>)",
+				config.InputAgeBasis:      "ready-for-review",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, AgeHours: 300}),
+				getTestPR(GetTestPROptions{Number: 2, AgeHours: 100}),
+			},
+			timelineEventsByPRNumber: map[int][]*github.Timeline{
+				1: {{
+					Event:     github.Ptr("ready_for_review"),
+					CreatedAt: &github.Timestamp{Time: time.Now().Add(-3 * time.Hour)},
+				}},
+			},
+			expectedPRNumbers: []int{1, 2},
+			expectedSummary:   "2 open PRs are waiting for attention 👀",
+			expectedHeadings:  []string{"New PRs (1)", "Old PRs (1)"},
+			expectedPRNumbersByHeading: map[string][]int{
+				"New PRs (1)": {1},
+				"Old PRs (1)": {2},
+			},
+		},
+		{
+			name:   "PRs filtered by age in expression measured from when marked ready for review",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGlobalFilters: "{\"expression\": \"age < 24\"}",
+				config.InputAgeBasis:      "ready-for-review",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, AgeHours: 300}),
+				getTestPR(GetTestPROptions{Number: 2, AgeHours: 100}),
+			},
+			timelineEventsByPRNumber: map[int][]*github.Timeline{
+				1: {{
+					Event:     github.Ptr("ready_for_review"),
+					CreatedAt: &github.Timestamp{Time: time.Now().Add(-3 * time.Hour)},
+				}},
+			},
+			expectedPRNumbers: []int{1},
+			expectedSummary:   "1 open PR is waiting for attention 👀",
+		},
+		{
+			name:   "PR age measured in business hours",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputAgeCategories:    "0: New PRs (<pr_count>); 41: Old PRs (<pr_count>)",
+				config.InputBusinessHoursAge: true,
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, AgeHours: 7 * 24}), // 40 working hours
+				getTestPR(GetTestPROptions{Number: 2, AgeHours: 14 * 24}),
+			},
+			expectedPRNumbers: []int{1, 2},
+			expectedSummary:   "2 open PRs are waiting for attention 👀",
+			expectedHeadings:  []string{"New PRs (1)", "Old PRs (1)"},
+			expectedPRNumbersByHeading: map[string][]int{
+				"New PRs (1)": {1},
+				"Old PRs (1)": {2},
+			},
+		},
+		{
+			name:             "invalid working calendar input",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputWorkingCalendar: `{"timezone": "Mars/Olympus"}`},
+			expectedErrorMsg: "configuration error: invalid input working-calendar: unknown timezone Mars/Olympus",
+		},
+		{
+			name:             "invalid working hours in working calendar input",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputWorkingCalendar: `{"working-hours": "17:00-09:00"}`},
+			expectedErrorMsg: "configuration error: invalid input working-calendar: invalid working hours 17:00-09:00 (expected format HH:MM-HH:MM with start before end)",
+		},
+		{
+			name:   "no message sent on holidays",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputSkipNonWorkingDays: true,
+				config.InputWorkingCalendar: fmt.Sprintf(
+					`{"working-days": ["mon", "tue", "wed", "thu", "fri", "sat", "sun"], "holidays": ["%s"]}`,
+					time.Now().UTC().Format("2006-01-02"),
+				),
+			},
+			fetchPRsError: errors.New("PRs should not be fetched"),
+		},
+		{
+			name:   "message sent on working days",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputSkipNonWorkingDays: true,
+				config.InputWorkingCalendar:    `{"working-days": ["mon", "tue", "wed", "thu", "fri", "sat", "sun"]}`,
+			},
+			prs:               []*github.PullRequest{getTestPR(GetTestPROptions{Number: 1})},
+			expectedPRNumbers: []int{1},
+			expectedSummary:   "1 open PR is waiting for attention 👀",
+		},
+		{
+			name:             "invalid holidays iCal path input",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputHolidaysICalPath: "missing-holidays.ics"},
+			expectedErrorMsg: "configuration error: error reading input holidays-ical-path: open missing-holidays.ics: no such file or directory",
+		},
+		{
+			name:   "PRs grouped by repository",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGithubRepositories: "some-org/repo2; some-org/repo1",
+				config.InputGroupBy:            "repository",
+			},
+			prsByRepo: map[string][]*github.PullRequest{
+				"repo1": {getTestPR(GetTestPROptions{Number: 1})},
+				"repo2": {getTestPR(GetTestPROptions{Number: 2}), getTestPR(GetTestPROptions{Number: 3})},
+			},
+			expectedPRNumbers: []int{1, 2, 3},
+			expectedSummary:   "3 open PRs are waiting for attention 👀",
+			expectedHeadings:  []string{"some-org/repo1 (1)", "some-org/repo2 (2)"},
+			expectedPRNumbersByHeading: map[string][]int{
+				"some-org/repo1 (1)": {1},
+				"some-org/repo2 (2)": {2, 3},
+			},
+		},
+		{
+			name:   "invalid group by input: used with age categories",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGroupBy:       "repository",
+				config.InputAgeCategories: "0: New PRs; 24: Old PRs",
+			},
+			expectedErrorMsg: "configuration error: cannot use both group-by and age-categories",
+		},
+		{
+			name:   "PRs grouped by label",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGroupBy:     "label",
+				config.InputLabelGroups: "Search: team:search; Payments: team:payments, area/billing*",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, Labels: []string{"team:payments"}}),
+				getTestPR(GetTestPROptions{Number: 2, Labels: []string{"bug", "area/billing-api"}}),
+				getTestPR(GetTestPROptions{Number: 3, Labels: []string{"team:search", "team:payments"}}),
+				getTestPR(GetTestPROptions{Number: 4, Labels: []string{"bug"}}),
+			},
+			expectedPRNumbers:     []int{1, 2, 3, 4},
+			expectedSummary:       "4 open PRs are waiting for attention 👀",
+			expectedHeadings:      []string{"Search (1)", "Payments (3)", "Ungrouped (1)"},
+			expectedListItemCount: 5,
+			expectedPRNumbersByHeading: map[string][]int{
+				"Search (1)":    {3},
+				"Payments (3)":  {1, 2, 3},
+				"Ungrouped (1)": {4},
+			},
+		},
+		{
+			name:   "PRs grouped by label without empty groups",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGroupBy:     "label",
+				config.InputLabelGroups: "Search: team:search; Payments: team:payments",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, Labels: []string{"team:payments"}}),
+				getTestPR(GetTestPROptions{Number: 2, Labels: []string{"team:payments", "bug"}}),
+			},
+			expectedPRNumbers: []int{1, 2},
+			expectedSummary:   "2 open PRs are waiting for attention 👀",
+			expectedHeadings:  []string{"Payments (2)"},
+			expectedPRNumbersByHeading: map[string][]int{
+				"Payments (2)": {1, 2},
+			},
+		},
+		{
+			name:             "invalid group by input: label groups not set",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputGroupBy: "label"},
+			expectedErrorMsg: "configuration error: if group-by is label, label-groups must also be set",
+		},
+		{
+			name:             "invalid label groups input",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputLabelGroups: "Payments"},
+			expectedErrorMsg: `configuration error: invalid label group in input label-groups: Payments (expected format "<group name>: <label pattern>, ...")`,
+		},
+		{
+			name:   "PRs grouped by requested reviewer",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGroupBy:                      "reviewer",
+				config.InputReviewerDisplay:              "mention",
+				config.InputSlackUserIdByGitHubUsername:  "bob: U2345678901",
+				config.InputSlackUserGroupIdByGitHubTeam: "platform-team: S1234567890",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, RequestedReviewers: []string{"bob", "alice"}}),
+				getTestPR(GetTestPROptions{Number: 2, RequestedReviewers: []string{"bob"}}),
+				getTestPR(GetTestPROptions{Number: 3, RequestedTeams: []string{"platform-team"}}),
+				getTestPR(GetTestPROptions{Number: 4}),
+			},
+			expectedPRNumbers: []int{1, 2, 3, 4},
+			expectedSummary:   "4 open PRs are waiting for attention 👀",
+			expectedHeadings: []string{
+				"alice (1)", "bob (2)", "platform-team (1)", "Ungrouped (1)",
+			},
+			expectedPRNumbersByHeading: map[string][]int{
+				"alice (1)":         {1},
+				"bob (2)":           {1, 2},
+				"platform-team (1)": {3},
+				"Ungrouped (1)":     {4},
+			},
+			expectedListItemCount: 5,
+			expectedMentions:      []string{"<@U2345678901>", "<!subteam^S1234567890>"},
+		},
+		{
+			name:   "PRs grouped by author",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGroupBy:                     "author",
+				config.InputSlackUserIdByGitHubUsername: "alice: U1234567890",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, AuthorLogin: "alice", AuthorName: "Alice"}),
+				getTestPR(GetTestPROptions{Number: 2, AuthorLogin: "alice", AuthorName: "Alice"}),
+				getTestPR(GetTestPROptions{Number: 3, AuthorLogin: "alice", AuthorName: "Alice", HeadSHA: "sha3"}),
+				getTestPR(GetTestPROptions{
+					Number: 4, AuthorLogin: "bob", AuthorName: "Bob", Mergeable: github.Ptr(false),
+				}),
+			},
+			reviewsByPRNumber: map[int][]*github.PullRequestReview{
+				2: {{User: &github.User{Login: github.Ptr("bob")}, State: github.Ptr("CHANGES_REQUESTED")}},
+			},
+			checkRunsByHeadSHA: map[string][]*github.CheckRun{
+				"sha3": {{Status: github.Ptr("completed"), Conclusion: github.Ptr("failure")}},
+			},
+			expectedPRNumbers: []int{1, 2, 3, 4},
+			expectedSummary:   "4 open PRs are waiting for attention 👀",
+			expectedHeadings:  []string{"Alice (3)", "Bob (1)"},
+			expectedPRNumbersByHeading: map[string][]int{
+				"Alice (3)": {1, 2, 3},
+				"Bob (1)":   {4},
+			},
+			expectedMentions: []string{"<@U1234567890>"},
+		},
+		{
+			name:   "PRs sorted oldest first",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputSortBy: "oldest",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, AgeHours: 2}),
+				getTestPR(GetTestPROptions{Number: 2, AgeHours: 30}),
+				getTestPR(GetTestPROptions{Number: 3, AgeHours: 10}),
+			},
+			expectedPRNumbers: []int{1, 2, 3},
+			expectedSummary:   "3 open PRs are waiting for attention 👀",
+			expectedPROrder:   []int{2, 3, 1},
+		},
+		{
+			name:   "PRs sorted by size",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputSortBy: "size",
+			},
+			prs: []*github.PullRequest{
+				getTestPR(GetTestPROptions{Number: 1, LinesChanged: 500}),
+				getTestPR(GetTestPROptions{Number: 2, LinesChanged: 5}),
+				getTestPR(GetTestPROptions{Number: 3, LinesChanged: 50}),
+			},
+			expectedPRNumbers: []int{1, 2, 3},
+			expectedSummary:   "3 open PRs are waiting for attention 👀",
+			expectedPROrder:   []int{2, 3, 1},
+		},
+		{
+			name:             "invalid sort by input",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputSortBy: "random"},
+			expectedErrorMsg: "configuration error: invalid value for input sort-by: random (must be one of [newest oldest updated approvals size repository])",
+		},
+		{
+			name:             "invalid age basis input",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputAgeBasis: "merged"},
+			expectedErrorMsg: "configuration error: invalid value for input age-basis: merged (must be one of [created ready-for-review updated review-requested])",
+		},
+		{
+			name:             "invalid age categories input: no category for new PRs",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputAgeCategories: "24: Old PRs"},
+			expectedErrorMsg: "configuration error: input age-categories must include a category for the newest PRs (min age 0)",
+		},
+		{
+			name:   "invalid age categories input: used with old PR threshold",
+			config: testhelpers.GetDefaultConfigFull(),
+			configOverrides: &map[string]any{
+				config.InputAgeCategories: "0: New PRs; 24: Old PRs",
+			},
+			expectedErrorMsg: "configuration error: cannot use both age-categories and old-pr-threshold-hours",
+		},
+		{
+			name:   "invalid repository filters input: conflicts with merged global filters",
+			config: testhelpers.GetDefaultConfigMinimal(),
+			configOverrides: &map[string]any{
+				config.InputGlobalFilters:        "{\"authors\": [\"alice\"]}",
+				config.InputRepositoryFilters:    "repo1: {\"authors-ignore\": [\"bob\"]}",
+				config.InputFiltersMergeStrategy: "merge",
+			},
+			expectedErrorMsg: "configuration error: invalid filters for repository repo1 when merged with global filters: cannot use both authors and authors-ignore filters at the same time",
+		},
+		{
+			name:             "main list heading input unset without age categories or groups",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputMainListHeading: ""},
+			expectedErrorMsg: "configuration error: main-list-heading must be set unless age-categories or group-by is used",
+		},
+		{
+			name:             "invalid ready to merge approvals input",
+			config:           testhelpers.GetDefaultConfigMinimal(),
+			configOverrides:  &map[string]any{config.InputReadyToMergeApprovals: 0},
+			expectedErrorMsg: "configuration error: ready-to-merge-approvals must be a positive integer",
+		},
 		{
 			name:   "PRs by user in one repo filtered",
 			config: testhelpers.GetDefaultConfigMinimal(),
@@ -369,7 +1049,14 @@ func TestScenarios(t *testing.T) {
 			testhelpers.SetTestEnvironment(t, tc.config, tc.configOverrides)
 
 			getGitHubClient := mockgithubclient.MakeMockGitHubClientGetter(
-				tc.prs, tc.prsByRepo, cmp.Or(tc.fetchPRsStatus, 200), tc.fetchPRsError, tc.reviewsByPRNumber,
+				tc.prs,
+				tc.prsByRepo,
+				cmp.Or(tc.fetchPRsStatus, 200),
+				tc.fetchPRsError,
+				tc.fetchPRsErrorByRepo,
+				tc.reviewsByPRNumber,
+				tc.checkRunsByHeadSHA,
+				tc.timelineEventsByPRNumber,
 			)
 			mockSlackAPI := mockslackclient.GetMockSlackAPI(tc.foundSlackChannels, tc.findChannelError, tc.sendMessageError)
 			getSlackClient := mockslackclient.MakeSlackClientGetter(mockSlackAPI)
@@ -398,6 +1085,9 @@ func TestScenarios(t *testing.T) {
 					mockSlackAPI.SentMessage.Text,
 				)
 			}
+			if tc.expectedFooterText != "" && !mockSlackAPI.SentMessage.Blocks.ContainsContextText(tc.expectedFooterText) {
+				t.Errorf("Expected footer text '%s' to be in the sent message blocks", tc.expectedFooterText)
+			}
 			if tc.expectedErrorMsg != "" {
 				return
 			}
@@ -412,14 +1102,15 @@ func TestScenarios(t *testing.T) {
 					}
 				}
 			}
-			if len(expectedPRs) != mockSlackAPI.SentMessage.Blocks.GetPRCount() {
+			expectedListItemCount := cmp.Or(tc.expectedListItemCount, len(expectedPRs))
+			if expectedListItemCount != mockSlackAPI.SentMessage.Blocks.GetPRCount() {
 				t.Errorf(
 					"Expected %v PRs to be included in the message (was %v)",
-					len(expectedPRs), mockSlackAPI.SentMessage.Blocks.GetPRCount(),
+					expectedListItemCount, mockSlackAPI.SentMessage.Blocks.GetPRCount(),
 				)
 			}
 			expectedHeading := ""
-			if len(expectedPRs) > 0 {
+			if len(expectedPRs) > 0 && len(tc.expectedHeadings) == 0 {
 				expectedHeading = strings.ReplaceAll(
 					tc.config.ContentInputs.MainListHeading, "<pr_count>", strconv.Itoa(len(expectedPRs)),
 				)
@@ -429,6 +1120,42 @@ func TestScenarios(t *testing.T) {
 					"Expected PR list heading '%s' to be included in the Slack message", expectedHeading,
 				)
 			}
+			if len(tc.expectedPROrder) > 0 {
+				listedPRs := getListedPRNumbers(mockSlackAPI.SentMessage.Blocks, tc.prs)
+				if !slices.Equal(listedPRs, tc.expectedPROrder) {
+					t.Errorf("Expected PRs to be listed in order %v, got %v", tc.expectedPROrder, listedPRs)
+				}
+			}
+			for _, mention := range tc.expectedMentions {
+				if !mockSlackAPI.SentMessage.Blocks.ContainsSectionText(mention) {
+					t.Errorf("Expected mention '%s' to be included in the Slack message", mention)
+				}
+			}
+			for _, heading := range tc.expectedHeadings {
+				if !mockSlackAPI.SentMessage.Blocks.ContainsHeading(heading) {
+					t.Errorf("Expected PR list heading '%s' to be included in the Slack message", heading)
+				}
+			}
+			headings := slices.DeleteFunc(mockSlackAPI.SentMessage.Blocks.GetHeadings(), func(heading string) bool {
+				return !slices.Contains(tc.expectedHeadings, heading)
+			})
+			if len(tc.expectedHeadings) > 0 && !slices.Equal(headings, tc.expectedHeadings) {
+				t.Errorf("Expected PR list headings %v in this order, got %v", tc.expectedHeadings, headings)
+			}
+			for heading, prNumbers := range tc.expectedPRNumbersByHeading {
+				listItems := mockSlackAPI.SentMessage.Blocks.GetPRListItems(heading)
+				expectedPRs := filterPRsByNumbers(tc.prs, tc.prsByRepo, prNumbers)
+				if len(listItems) != len(expectedPRs) {
+					t.Errorf("Expected %d PRs under heading '%s', got %v", len(expectedPRs), heading, listItems)
+				}
+				for _, pr := range expectedPRs {
+					if !slices.ContainsFunc(listItems, func(item string) bool {
+						return strings.Contains(item, pr.GetTitle())
+					}) {
+						t.Errorf("Expected PR '%s' to be listed under heading '%s'", pr.GetTitle(), heading)
+					}
+				}
+			}
 		})
 	}
 }
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
 	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/slackclient"
@@ -10,6 +12,7 @@ import (
 	"github.com/hellej/pr-slack-reminder-action/internal/messagebuilder"
 	"github.com/hellej/pr-slack-reminder-action/internal/messagecontent"
 	"github.com/hellej/pr-slack-reminder-action/internal/prparser"
+	"github.com/hellej/pr-slack-reminder-action/internal/workcalendar"
 )
 
 func Run(
@@ -21,6 +24,13 @@ func Run(
 		return fmt.Errorf("configuration error: %v", err)
 	}
 	config.Print()
+	if config.SkipNonWorkingDays && !config.WorkingCalendar.IsWorkingDay(time.Now()) {
+		log.Printf(
+			"Today (%s) is not a working day, exiting without sending a message",
+			time.Now().In(config.WorkingCalendar.Location).Format(workcalendar.DateLayout),
+		)
+		return nil
+	}
 	githubClient := getGitHubClient(config.GithubToken)
 	slackClient := getSlackClient(config.SlackBotToken)
 
@@ -33,12 +43,21 @@ func Run(
 		config.SlackChannelID = channelID
 	}
 
-	prs, err := githubClient.FetchOpenPRs(config.Repositories, config.GlobalFilters, config.RepositoryFilters)
-	if err != nil {
+	prs, err := githubClient.FetchOpenPRs(
+		config.Repositories, config.FetchInputs, config.GlobalFilters, config.RepositoryFilters,
+	)
+	var repositoryErrors githubclient.RepositoryErrors
+	if errors.As(err, &repositoryErrors) {
+		log.Printf("Continuing without %d repositories that could not be read", len(repositoryErrors))
+	} else if err != nil {
 		return err
 	}
-	parsedPRs := prparser.ParsePRs(prs, config.SlackUserIdByGitHubUsername)
-	content := messagecontent.GetContent(parsedPRs, config.ContentInputs)
+	parsedPRs := prparser.ParsePRs(
+		prs, config.SlackUserIdByGitHubUsername, config.SlackUserGroupIdByGitHubTeam, config.ContentInputs,
+	)
+	content := messagecontent.GetContent(
+		parsedPRs, repositoryErrors.GetRepositories(), config.ContentInputs,
+	)
 	if !content.HasPRs() && content.SummaryText == "" {
 		log.Println("No PRs found and no message configured for this case, exiting")
 		return nil
@@ -0,0 +1,96 @@
+package githubclient
+
+import (
+	"context"
+	"log"
+	"slices"
+
+	"github.com/google/go-github/v72/github"
+)
+
+// The aggregated CI status of the head commit of a PR
+type CIStatus string
+
+const (
+	CIStatusPassing CIStatus = "passing"
+	CIStatusFailing CIStatus = "failing"
+	CIStatusPending CIStatus = "pending"
+	CIStatusNone    CIStatus = "none" // no commit statuses or check runs
+	CIStatusUnknown CIStatus = ""     // the statuses could not be fetched
+)
+
+// Fetches both the commit statuses (combined status) and the check runs of the head commit
+// of the PR, as CI systems report their results using either of them.
+func (c *client) fetchCIStatus(owner string, repo string, pr *github.PullRequest, maxPages int) CIStatus {
+	ref := pr.GetHead().GetSHA()
+	if ref == "" {
+		return CIStatusUnknown
+	}
+	combinedStatus, _, err := c.repositoriesService.GetCombinedStatus(
+		context.Background(), owner, repo, ref, &github.ListOptions{PerPage: pageSize},
+	)
+	if err != nil {
+		log.Printf("Unable to fetch commit statuses for pull request %s/%s#%d: %v", owner, repo, pr.GetNumber(), err)
+		return CIStatusUnknown
+	}
+	checkRuns, err := fetchAllPages(maxPages, func(listOptions github.ListOptions) (
+		[]*github.CheckRun, *github.Response, error,
+	) {
+		result, response, err := c.checksService.ListCheckRunsForRef(
+			context.Background(), owner, repo, ref, &github.ListCheckRunsOptions{ListOptions: listOptions},
+		)
+		if result == nil {
+			return nil, response, err
+		}
+		return result.CheckRuns, response, err
+	})
+	if err != nil {
+		log.Printf("Unable to fetch check runs for pull request %s/%s#%d: %v", owner, repo, pr.GetNumber(), err)
+		return CIStatusUnknown
+	}
+
+	statuses := []CIStatus{}
+	if combinedStatus.GetTotalCount() > 0 {
+		statuses = append(statuses, getCommitStatusCIStatus(combinedStatus.GetState()))
+	}
+	for _, checkRun := range checkRuns.items {
+		statuses = append(statuses, getCheckRunCIStatus(checkRun))
+	}
+	return aggregateCIStatuses(statuses)
+}
+
+// Maps the state of a combined status (error, failure, pending or success) to a CI status.
+func getCommitStatusCIStatus(state string) CIStatus {
+	switch state {
+	case "success":
+		return CIStatusPassing
+	case "error", "failure":
+		return CIStatusFailing
+	}
+	return CIStatusPending
+}
+
+// Skipped and neutral check runs are considered passing.
+func getCheckRunCIStatus(checkRun *github.CheckRun) CIStatus {
+	if checkRun.GetStatus() != "completed" {
+		return CIStatusPending
+	}
+	switch checkRun.GetConclusion() {
+	case "failure", "timed_out", "cancelled", "action_required", "startup_failure":
+		return CIStatusFailing
+	}
+	return CIStatusPassing
+}
+
+// Any failing status makes the aggregate failing, otherwise any pending status makes it pending.
+func aggregateCIStatuses(statuses []CIStatus) CIStatus {
+	switch {
+	case len(statuses) == 0:
+		return CIStatusNone
+	case slices.Contains(statuses, CIStatusFailing):
+		return CIStatusFailing
+	case slices.Contains(statuses, CIStatusPending):
+		return CIStatusPending
+	}
+	return CIStatusPassing
+}
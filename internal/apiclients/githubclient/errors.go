@@ -0,0 +1,43 @@
+package githubclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+type RepositoryError struct {
+	// Full path of the repository, e.g. "owner/repo"
+	Repository string
+	Err        error
+}
+
+func (e RepositoryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e RepositoryError) Unwrap() error {
+	return e.Err
+}
+
+// Collects the errors of repositories from which PRs could not be fetched
+// (used when continuing on repository errors).
+type RepositoryErrors []RepositoryError
+
+func (e RepositoryErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, repoErr := range e {
+		messages[i] = repoErr.Error()
+	}
+	return fmt.Sprintf(
+		"unable to fetch pull requests from %d repositories: %s", len(e), strings.Join(messages, "; "),
+	)
+}
+
+// Returns the full paths of the repositories that could not be read.
+func (e RepositoryErrors) GetRepositories() []string {
+	repositories := make([]string, len(e))
+	for i, repoErr := range e {
+		repositories[i] = repoErr.Repository
+	}
+	return repositories
+}
@@ -0,0 +1,8 @@
+package githubclient
+
+import "time"
+
+// Allows tests to retry fetching the mergeability of PRs without waiting.
+func SetMergeableRetryDelay(c Client, delay time.Duration) {
+	c.(*client).mergeableRetryDelay = delay
+}
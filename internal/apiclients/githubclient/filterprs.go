@@ -1,25 +1,51 @@
 package githubclient
 
-import "github.com/hellej/pr-slack-reminder-action/internal/config"
+import (
+	"fmt"
 
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
+)
+
+// Filters the PRs with the repository specific filters (if any) and the global filters
+// according to the merge strategy of the fetch inputs. Each PR (by repository and number)
+// is included at most once.
 func filterPRs(
 	prs []PR,
+	fetchInputs config.FetchInputs,
 	globalFilters config.Filters,
 	repositoryFilters map[string]config.Filters,
 ) []PR {
 	filtered := make([]PR, 0, len(prs))
+	included := make(map[string]bool, len(prs))
 	for _, pr := range prs {
-		repositoryFilters, ok := repositoryFilters[pr.Repository]
-		if ok {
-			if pr.isMatch(repositoryFilters) {
-				filtered = append(filtered, pr)
-			} else {
-				continue
-			}
+		key := fmt.Sprintf("%s#%d", pr.GetRepositoryPath(), pr.GetNumber())
+		if included[key] {
+			continue
 		}
-		if pr.isMatch(globalFilters) {
+		if pr.matchesFilters(fetchInputs, globalFilters, repositoryFilters) {
+			included[key] = true
 			filtered = append(filtered, pr)
 		}
 	}
 	return filtered
 }
+
+func (pr PR) matchesFilters(
+	fetchInputs config.FetchInputs,
+	globalFilters config.Filters,
+	repositoryFilters map[string]config.Filters,
+) bool {
+	fields := pr.getExpressionFields(fetchInputs)
+	filters, ok := repositoryFilters[pr.Repository]
+	if !ok {
+		return pr.isMatch(globalFilters, fields)
+	}
+	switch fetchInputs.FiltersMergeStrategy {
+	case config.FiltersMerge:
+		return pr.isMatch(globalFilters.MergedWith(filters), fields)
+	case config.FiltersIntersect:
+		return pr.isMatch(globalFilters, fields) && pr.isMatch(filters, fields)
+	default:
+		return pr.isMatch(filters, fields)
+	}
+}
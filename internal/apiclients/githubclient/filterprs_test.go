@@ -0,0 +1,180 @@
+package githubclient
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
+)
+
+func newTestPR(repository string, number int, author string, labels ...string) PR {
+	githubLabels := make([]*github.Label, len(labels))
+	for i, label := range labels {
+		githubLabels[i] = &github.Label{Name: github.Ptr(label)}
+	}
+	return PR{
+		PullRequest: &github.PullRequest{Number: github.Ptr(number), Labels: githubLabels},
+		Owner:       "owner",
+		Repository:  repository,
+		Author:      Collaborator{Login: author},
+	}
+}
+
+func mustPatterns(patterns ...string) config.Patterns {
+	compiled, err := config.NewPatterns(patterns...)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
+
+func TestFilterPRs(t *testing.T) {
+	prs := []PR{
+		newTestPR("repo1", 1, "alice", "feature"),
+		newTestPR("repo1", 2, "bob", "feature"),
+		newTestPR("repo1", 3, "alice", "wip"),
+		newTestPR("repo1", 4, "bob", "fix"),
+		newTestPR("repo2", 1, "alice", "feature"),
+		newTestPR("repo2", 2, "bob", "wip"),
+	}
+	globalFilters := config.Filters{Labels: mustPatterns("feature", "fix"), AuthorsIgnore: mustPatterns("bob")}
+	repositoryFilters := map[string]config.Filters{
+		"repo1": {Authors: mustPatterns("bob")},
+	}
+
+	testCases := []struct {
+		name              string
+		mergeStrategy     config.FiltersMergeStrategy
+		expectedPRNumbers map[string][]int
+	}{
+		{
+			name:          "override (default)",
+			mergeStrategy: "",
+			expectedPRNumbers: map[string][]int{
+				"repo1": {2, 4}, "repo2": {1},
+			},
+		},
+		{
+			name:          "override",
+			mergeStrategy: config.FiltersOverride,
+			expectedPRNumbers: map[string][]int{
+				"repo1": {2, 4}, "repo2": {1},
+			},
+		},
+		{
+			// bob is both required (repository) and ignored (global)
+			name:          "merge",
+			mergeStrategy: config.FiltersMerge,
+			expectedPRNumbers: map[string][]int{
+				"repo1": {}, "repo2": {1},
+			},
+		},
+		{
+			name:          "intersect",
+			mergeStrategy: config.FiltersIntersect,
+			expectedPRNumbers: map[string][]int{
+				"repo1": {}, "repo2": {1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := filterPRs(prs, config.FetchInputs{FiltersMergeStrategy: tc.mergeStrategy}, globalFilters, repositoryFilters)
+			assertPRNumbers(t, filtered, tc.expectedPRNumbers)
+		})
+	}
+}
+
+func TestFilterPRsMerge(t *testing.T) {
+	prs := []PR{
+		newTestPR("repo1", 1, "alice", "feature"),
+		newTestPR("repo1", 2, "bob", "feature"),
+		newTestPR("repo1", 3, "carol", "wip"),
+		newTestPR("repo1", 4, "carol", "feature"),
+	}
+	globalFilters := config.Filters{Labels: mustPatterns("feature"), AuthorsIgnore: mustPatterns("bob")}
+	repositoryFilters := map[string]config.Filters{
+		"repo1": {AuthorsIgnore: mustPatterns("alice")},
+	}
+
+	testCases := []struct {
+		mergeStrategy     config.FiltersMergeStrategy
+		expectedPRNumbers map[string][]int
+	}{
+		{config.FiltersOverride, map[string][]int{"repo1": {2, 3, 4}}},
+		{config.FiltersMerge, map[string][]int{"repo1": {2, 4}}},
+		{config.FiltersIntersect, map[string][]int{"repo1": {4}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.mergeStrategy), func(t *testing.T) {
+			filtered := filterPRs(prs, config.FetchInputs{FiltersMergeStrategy: tc.mergeStrategy}, globalFilters, repositoryFilters)
+			assertPRNumbers(t, filtered, tc.expectedPRNumbers)
+		})
+	}
+}
+
+func TestFilterPRsDeduplication(t *testing.T) {
+	prs := []PR{
+		newTestPR("repo1", 1, "alice"),
+		newTestPR("repo1", 1, "alice"),
+		newTestPR("repo2", 1, "alice"),
+	}
+	repositoryFilters := map[string]config.Filters{"repo1": {Authors: mustPatterns("alice")}}
+
+	for _, mergeStrategy := range []config.FiltersMergeStrategy{
+		config.FiltersOverride, config.FiltersMerge, config.FiltersIntersect,
+	} {
+		t.Run(string(mergeStrategy), func(t *testing.T) {
+			filtered := filterPRs(prs, config.FetchInputs{FiltersMergeStrategy: mergeStrategy}, config.Filters{}, repositoryFilters)
+			assertPRNumbers(t, filtered, map[string][]int{"repo1": {1}, "repo2": {1}})
+		})
+	}
+}
+
+func assertPRNumbers(t *testing.T, prs []PR, expectedPRNumbers map[string][]int) {
+	t.Helper()
+	prNumbers := map[string][]int{}
+	for _, pr := range prs {
+		prNumbers[pr.Repository] = append(prNumbers[pr.Repository], pr.GetNumber())
+	}
+	for repository, expected := range expectedPRNumbers {
+		if !slices.Equal(prNumbers[repository], expected) && len(prNumbers[repository])+len(expected) > 0 {
+			t.Errorf("Expected PRs %v in %s, got %v", expected, repository, prNumbers[repository])
+		}
+	}
+}
+
+func TestFilterPRsSize(t *testing.T) {
+	withSize := func(pr PR, linesChanged int) PR {
+		pr.Size = &PRSize{Additions: linesChanged}
+		return pr
+	}
+	prs := []PR{
+		withSize(newTestPR("repo1", 1, "alice"), 5),
+		withSize(newTestPR("repo1", 2, "alice"), 50),
+		withSize(newTestPR("repo1", 3, "alice"), 500),
+		newTestPR("repo1", 4, "alice"), // unknown size
+	}
+	minSize, maxSize := 10, 100
+
+	testCases := []struct {
+		name              string
+		filters           config.Filters
+		expectedPRNumbers []int
+	}{
+		{"min-size", config.Filters{MinSize: &minSize}, []int{2, 3}},
+		{"max-size", config.Filters{MaxSize: &maxSize}, []int{1, 2}},
+		{"min-size and max-size", config.Filters{MinSize: &minSize, MaxSize: &maxSize}, []int{2}},
+		{"no size filters", config.Filters{}, []int{1, 2, 3, 4}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := filterPRs(prs, config.FetchInputs{}, tc.filters, nil)
+			assertPRNumbers(t, filtered, map[string][]int{"repo1": tc.expectedPRNumbers})
+		})
+	}
+}
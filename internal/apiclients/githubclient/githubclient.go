@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/go-github/v72/github"
 	"github.com/hellej/pr-slack-reminder-action/internal/config"
@@ -13,6 +16,7 @@ import (
 type Client interface {
 	FetchOpenPRs(
 		repositories []config.Repository,
+		fetchInputs config.FetchInputs,
 		globalFilters config.Filters,
 		repositoryFilters map[string]config.Filters,
 	) ([]PR, error)
@@ -29,28 +33,87 @@ type githubPullRequestsService interface {
 	) (
 		[]*github.PullRequestReview, *github.Response, error,
 	)
+	Get(
+		ctx context.Context, owner string, repo string, number int,
+	) (
+		*github.PullRequest, *github.Response, error,
+	)
+}
+
+type githubRepositoriesService interface {
+	GetCombinedStatus(
+		ctx context.Context, owner string, repo string, ref string, opts *github.ListOptions,
+	) (
+		*github.CombinedStatus, *github.Response, error,
+	)
+}
+
+type githubChecksService interface {
+	ListCheckRunsForRef(
+		ctx context.Context, owner string, repo string, ref string, opts *github.ListCheckRunsOptions,
+	) (
+		*github.ListCheckRunsResults, *github.Response, error,
+	)
+}
+
+const (
+	defaultMergeableRetries    = 3
+	defaultMergeableRetryDelay = 2 * time.Second
+	// The maximum number of PRs whose reviews and details are fetched at the same time (to
+	// avoid hitting the secondary rate limits of the GitHub API with many open PRs)
+	maxConcurrentPRFetches = 10
+)
+
+type githubIssuesService interface {
+	ListIssueTimeline(
+		ctx context.Context, owner string, repo string, number int, opts *github.ListOptions,
+	) (
+		[]*github.Timeline, *github.Response, error,
+	)
 }
 
 type client struct {
-	prsService githubPullRequestsService
+	prsService          githubPullRequestsService
+	repositoriesService githubRepositoriesService
+	checksService       githubChecksService
+	issuesService       githubIssuesService
+	// How many times and how often the details of a PR are refetched if its mergeability
+	// has not been computed yet
+	mergeableRetries    int
+	mergeableRetryDelay time.Duration
 }
 
-func NewClient(prsService githubPullRequestsService) Client {
-	return &client{prsService: prsService}
+func NewClient(
+	prsService githubPullRequestsService,
+	repositoriesService githubRepositoriesService,
+	checksService githubChecksService,
+	issuesService githubIssuesService,
+) Client {
+	return &client{
+		prsService:          prsService,
+		repositoriesService: repositoriesService,
+		checksService:       checksService,
+		issuesService:       issuesService,
+		mergeableRetries:    defaultMergeableRetries,
+		mergeableRetryDelay: defaultMergeableRetryDelay,
+	}
 }
 
 func GetAuthenticatedClient(token string) Client {
 	ghClient := github.NewClient(nil).WithAuthToken(token)
-	return NewClient(ghClient.PullRequests)
+	return NewClient(ghClient.PullRequests, ghClient.Repositories, ghClient.Checks, ghClient.Issues)
 }
 
 // Returns an error if fetching PRs from any repository fails (and cancels other requests).
+// If fetchInputs.ContinueOnRepositoryErrors is set, the PRs of the other repositories are
+// returned together with a RepositoryErrors error listing the repositories that failed.
 //
 // The wait group & cancellation logic could be refactored to use errgroup package for more
 // concise implementation. However, the current implementation also serves as learning material
 // so we can save the refactoring for later...
 func (c *client) FetchOpenPRs(
 	repositories []config.Repository,
+	fetchInputs config.FetchInputs,
 	globalFilters config.Filters,
 	repositoryFilters map[string]config.Filters,
 ) ([]PR, error) {
@@ -65,10 +128,12 @@ func (c *client) FetchOpenPRs(
 		wg.Add(1)
 		go func(r config.Repository) {
 			defer wg.Done()
-			apiResult := c.fetchOpenPRsForRepository(ctx, r.Owner, r.Name)
+			apiResult := c.fetchOpenPRsForRepository(ctx, r.Owner, r.Name, fetchInputs.MaxPages)
 			apiResultChannel <- apiResult
 			if apiResult.err != nil {
-				cancel()
+				if !fetchInputs.ContinueOnRepositoryErrors {
+					cancel()
+				}
 			} else {
 				logFoundPRs(r.Path, apiResult.prs)
 			}
@@ -81,26 +146,50 @@ func (c *client) FetchOpenPRs(
 	}()
 
 	successfulResults := []PRsOfRepoResult{}
+	repositoryErrors := RepositoryErrors{}
 	for result := range apiResultChannel {
 		if result.err != nil {
-			return nil, result.err
+			if !fetchInputs.ContinueOnRepositoryErrors {
+				return nil, result.err
+			}
+			log.Printf("Skipping repository %s/%s: %v", result.owner, result.repository, result.err)
+			repositoryErrors = append(repositoryErrors, RepositoryError{
+				Repository: result.owner + "/" + result.repository,
+				Err:        result.err,
+			})
 		} else {
 			successfulResults = append(successfulResults, result)
 		}
 	}
 
-	return filterPRs(
-		c.addReviewerInfoToPRs(successfulResults),
+	prs := filterPRs(
+		c.addDetailsToPRs(successfulResults, fetchInputs),
+		fetchInputs,
 		globalFilters,
 		repositoryFilters,
-	), nil
+	)
+	if len(repositoryErrors) > 0 {
+		slices.SortFunc(repositoryErrors, func(a, b RepositoryError) int {
+			return strings.Compare(a.Repository, b.Repository)
+		})
+		return prs, repositoryErrors
+	}
+	return prs, nil
 }
 
 func (c *client) fetchOpenPRsForRepository(
-	ctx context.Context, repoOwner string, repoName string,
+	ctx context.Context, repoOwner string, repoName string, maxPages int,
 ) PRsOfRepoResult {
-	prs, response, err := c.prsService.List(ctx, repoOwner, repoName, nil)
+	result, err := fetchAllPages(maxPages, func(listOptions github.ListOptions) (
+		[]*github.PullRequest, *github.Response, error,
+	) {
+		return c.prsService.List(ctx, repoOwner, repoName, &github.PullRequestListOptions{
+			State:       "open",
+			ListOptions: listOptions,
+		})
+	})
 	if err != nil {
+		response := result.response
 		if response != nil && response.StatusCode == 404 {
 			return PRsOfRepoResult{
 				prs:        nil,
@@ -114,13 +203,21 @@ func (c *client) fetchOpenPRsForRepository(
 
 		} else {
 			return PRsOfRepoResult{
-				prs: nil,
-				err: fmt.Errorf("error fetching pull requests from %s/%s: %v", repoOwner, repoName, err),
+				prs:        nil,
+				owner:      repoOwner,
+				repository: repoName,
+				err:        fmt.Errorf("error fetching pull requests from %s/%s: %v", repoOwner, repoName, err),
 			}
 		}
 	}
+	if result.limitReached {
+		log.Printf(
+			"Reached the limit of %d pages when fetching pull requests from %s/%s, some PRs may be missing",
+			maxPages, repoOwner, repoName,
+		)
+	}
 	return PRsOfRepoResult{
-		prs:        prs,
+		prs:        result.items,
 		owner:      repoOwner,
 		repository: repoName,
 		err:        nil,
@@ -134,8 +231,11 @@ func logFoundPRs(repository string, prs []*github.PullRequest) {
 	}
 }
 
-func (c *client) addReviewerInfoToPRs(prResults []PRsOfRepoResult) []PR {
-	log.Printf("Fetching pull request reviewers for PRs")
+// Fetches the reviews of each PR, and the details, the CI status and the timeline events
+// if needed.
+func (c *client) addDetailsToPRs(prResults []PRsOfRepoResult, fetchInputs config.FetchInputs) []PR {
+	maxPages := fetchInputs.MaxPages
+	log.Printf("Fetching pull request reviewers and details for PRs")
 
 	totalPRCount := 0
 	for _, result := range prResults {
@@ -143,30 +243,45 @@ func (c *client) addReviewerInfoToPRs(prResults []PRsOfRepoResult) []PR {
 	}
 
 	resultChannel := make(chan FetchReviewsResult, totalPRCount)
+	semaphore := make(chan struct{}, maxConcurrentPRFetches)
 	var wg sync.WaitGroup
 
 	for _, result := range prResults {
 		for _, pullRequest := range result.prs {
 			wg.Add(1)
+			semaphore <- struct{}{}
 			go func(owner string, repo string, pr *github.PullRequest) {
 				defer wg.Done()
-				reviews, response, err := c.prsService.ListReviews(context.Background(), owner, repo, *pr.Number, nil)
+				defer func() { <-semaphore }()
+				result, err := fetchAllPages(maxPages, func(listOptions github.ListOptions) (
+					[]*github.PullRequestReview, *github.Response, error,
+				) {
+					return c.prsService.ListReviews(context.Background(), owner, repo, *pr.Number, &listOptions)
+				})
 				if err != nil {
-					err = fmt.Errorf(
-						"error fetching reviews for pull request %s/%s#%d: %v/%v",
-						owner,
-						repo,
-						*pr.Number,
-						response.Status,
-						err,
+					err = reviewsFetchError(owner, repo, *pr.Number, result.response, err)
+				} else if result.limitReached {
+					log.Printf(
+						"Reached the limit of %d pages when fetching reviews for pull request %s/%s#%d, some reviews may be missing",
+						maxPages, owner, repo, *pr.Number,
 					)
 				}
 				prWithReviews := FetchReviewsResult{
 					pr:         pr,
-					reviews:    reviews,
+					reviews:    result.items,
+					owner:      owner,
 					repository: repo,
 					err:        err,
 				}
+				if fetchInputs.FetchSize || fetchInputs.FetchMergeability {
+					prWithReviews.details = c.fetchPRDetails(owner, repo, pr, fetchInputs.FetchMergeability)
+				}
+				if fetchInputs.FetchCIStatus {
+					prWithReviews.ciStatus = c.fetchCIStatus(owner, repo, pr, maxPages)
+				}
+				if fetchInputs.FetchTimelineEvents {
+					prWithReviews.timelineTimes = c.fetchTimelineTimes(owner, repo, pr, maxPages)
+				}
 				resultChannel <- prWithReviews
 
 			}(result.owner, result.repository, pullRequest)
@@ -185,3 +300,65 @@ func (c *client) addReviewerInfoToPRs(prResults []PRsOfRepoResult) []PR {
 	}
 	return allPRs
 }
+
+// Fetches the full details of the PR as the PR list response does not include e.g. the size
+// and mergeability of the PR. Returns nil if the details cannot be fetched.
+//
+// GitHub computes the mergeability of a PR in the background (triggered by the request), so
+// mergeable is null until the computation is done. In that case the details are refetched
+// a few times if the mergeability is needed, after which mergeable is left null (unknown).
+func (c *client) fetchPRDetails(
+	owner string, repo string, pr *github.PullRequest, waitForMergeability bool,
+) *github.PullRequest {
+	details, err := c.getPRDetails(owner, repo, pr.GetNumber())
+	if err != nil {
+		log.Printf("Unable to fetch details for pull request %s/%s#%d: %v", owner, repo, pr.GetNumber(), err)
+		return nil
+	}
+	if !waitForMergeability {
+		return details
+	}
+	for retry := 1; details.Mergeable == nil && retry <= c.mergeableRetries; retry++ {
+		time.Sleep(c.mergeableRetryDelay)
+		retried, err := c.getPRDetails(owner, repo, pr.GetNumber())
+		if err != nil {
+			log.Printf(
+				"Unable to refetch details for pull request %s/%s#%d: %v", owner, repo, pr.GetNumber(), err,
+			)
+			break
+		}
+		details = retried
+	}
+	if details.Mergeable == nil {
+		log.Printf("Mergeability of pull request %s/%s#%d is unknown", owner, repo, pr.GetNumber())
+	}
+	return details
+}
+
+func (c *client) getPRDetails(owner string, repo string, number int) (*github.PullRequest, error) {
+	details, response, err := c.prsService.Get(context.Background(), owner, repo, number)
+	if err != nil {
+		if response != nil && response.Response != nil {
+			return nil, fmt.Errorf("%v/%v", response.Status, err)
+		}
+		return nil, err
+	}
+	if details == nil {
+		return nil, fmt.Errorf("empty response")
+	}
+	return details, nil
+}
+
+func reviewsFetchError(
+	owner string, repo string, prNumber int, response *github.Response, err error,
+) error {
+	if response != nil && response.Response != nil {
+		return fmt.Errorf(
+			"error fetching reviews for pull request %s/%s#%d: %v/%v",
+			owner, repo, prNumber, response.Status, err,
+		)
+	}
+	return fmt.Errorf(
+		"error fetching reviews for pull request %s/%s#%d: %v", owner, repo, prNumber, err,
+	)
+}
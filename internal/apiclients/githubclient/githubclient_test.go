@@ -1,9 +1,18 @@
 package githubclient_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/go-github/v72/github"
 	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
 )
 
 func TestGetAuthenticatedClient(t *testing.T) {
@@ -12,3 +21,574 @@ func TestGetAuthenticatedClient(t *testing.T) {
 		t.Fatal("Expected non-nil client, got nil")
 	}
 }
+
+// Serves one PR (and one review and check run) per page to test pagination.
+type pagingPullRequestsService struct {
+	prs        []*github.PullRequest
+	reviews    []*github.PullRequestReview
+	reviewsErr error
+	getErr     error
+	// The number of Get requests for which mergeable is null (not yet computed)
+	unknownMergeableGets int
+	getCount             int
+	getMutex             sync.Mutex // PR details are fetched concurrently
+	combinedStatus       *github.CombinedStatus
+	checkRuns            []*github.CheckRun
+	timelineEvents       []*github.Timeline
+	listedPages          []int
+}
+
+func pageOf[T any](items []T, page int) ([]T, *github.Response) {
+	index := max(page, 1) - 1
+	response := &github.Response{Response: &http.Response{StatusCode: 200}}
+	if index >= len(items) {
+		return nil, response
+	}
+	if index < len(items)-1 {
+		response.NextPage = index + 2
+	}
+	return items[index : index+1], response
+}
+
+func (s *pagingPullRequestsService) List(
+	ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions,
+) ([]*github.PullRequest, *github.Response, error) {
+	s.listedPages = append(s.listedPages, opts.Page)
+	prs, response := pageOf(s.prs, opts.Page)
+	return prs, response, nil
+}
+
+func (s *pagingPullRequestsService) ListReviews(
+	ctx context.Context, owner string, repo string, number int, opts *github.ListOptions,
+) ([]*github.PullRequestReview, *github.Response, error) {
+	if s.reviewsErr != nil {
+		return nil, nil, s.reviewsErr
+	}
+	reviews, response := pageOf(s.reviews, opts.Page)
+	return reviews, response, nil
+}
+
+func (s *pagingPullRequestsService) Get(
+	ctx context.Context, owner string, repo string, number int,
+) (*github.PullRequest, *github.Response, error) {
+	if s.getErr != nil {
+		return nil, &github.Response{Response: &http.Response{StatusCode: 500, Status: "500"}}, s.getErr
+	}
+	s.getMutex.Lock()
+	defer s.getMutex.Unlock()
+	s.getCount++
+	for _, pr := range s.prs {
+		if pr.GetNumber() == number {
+			details := *pr
+			if s.getCount <= s.unknownMergeableGets {
+				details.Mergeable = nil
+			} else if details.Mergeable == nil {
+				details.Mergeable = github.Ptr(true)
+			}
+			return &details, &github.Response{Response: &http.Response{StatusCode: 200}}, nil
+		}
+	}
+	return nil, &github.Response{Response: &http.Response{StatusCode: 404, Status: "404"}}, errors.New("not found")
+}
+
+func (s *pagingPullRequestsService) GetCombinedStatus(
+	ctx context.Context, owner string, repo string, ref string, opts *github.ListOptions,
+) (*github.CombinedStatus, *github.Response, error) {
+	return s.combinedStatus, &github.Response{Response: &http.Response{StatusCode: 200}}, nil
+}
+
+func (s *pagingPullRequestsService) ListCheckRunsForRef(
+	ctx context.Context, owner string, repo string, ref string, opts *github.ListCheckRunsOptions,
+) (*github.ListCheckRunsResults, *github.Response, error) {
+	checkRuns, response := pageOf(s.checkRuns, opts.Page)
+	return &github.ListCheckRunsResults{CheckRuns: checkRuns}, response, nil
+}
+
+func (s *pagingPullRequestsService) ListIssueTimeline(
+	ctx context.Context, owner string, repo string, number int, opts *github.ListOptions,
+) ([]*github.Timeline, *github.Response, error) {
+	events, response := pageOf(s.timelineEvents, opts.Page)
+	return events, response, nil
+}
+
+func TestFetchOpenPRsPagination(t *testing.T) {
+	newService := func() *pagingPullRequestsService {
+		service := &pagingPullRequestsService{}
+		for i := 1; i <= 3; i++ {
+			service.prs = append(service.prs, &github.PullRequest{
+				Number: github.Ptr(i),
+				User:   &github.User{Login: github.Ptr("author")},
+			})
+			service.reviews = append(service.reviews, &github.PullRequestReview{
+				User:  &github.User{Login: github.Ptr(fmt.Sprintf("reviewer%d", i))},
+				State: github.Ptr("APPROVED"),
+			})
+		}
+		return service
+	}
+	repositories := []config.Repository{{Path: "owner/repo", Owner: "owner", Name: "repo"}}
+
+	testCases := []struct {
+		name              string
+		maxPages          int
+		expectedPRCount   int
+		expectedApprovals int
+	}{
+		{name: "all pages fetched", maxPages: 10, expectedPRCount: 3, expectedApprovals: 3},
+		{name: "page limit reached", maxPages: 2, expectedPRCount: 2, expectedApprovals: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := newService()
+			client := githubclient.NewClient(service, service, service, service)
+			prs, err := client.FetchOpenPRs(
+				repositories, config.FetchInputs{MaxPages: tc.maxPages}, config.Filters{}, nil,
+			)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(prs) != tc.expectedPRCount {
+				t.Errorf("Expected %d PRs, got %d", tc.expectedPRCount, len(prs))
+			}
+			if len(service.listedPages) != tc.expectedPRCount {
+				t.Errorf("Expected %d pages to be listed, got %v", tc.expectedPRCount, service.listedPages)
+			}
+			for _, pr := range prs {
+				if len(pr.ApprovedByUsers) != tc.expectedApprovals {
+					t.Errorf(
+						"Expected %d approvals for PR #%d, got %d",
+						tc.expectedApprovals, pr.GetNumber(), len(pr.ApprovedByUsers),
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchOpenPRsReviewsError(t *testing.T) {
+	service := &pagingPullRequestsService{
+		prs: []*github.PullRequest{{
+			Number: github.Ptr(1),
+			User:   &github.User{Login: github.Ptr("author")},
+		}},
+		reviewsErr: errors.New("connection reset"),
+	}
+	repositories := []config.Repository{{Path: "owner/repo", Owner: "owner", Name: "repo"}}
+
+	prs, err := githubclient.NewClient(service, service, service, service).FetchOpenPRs(
+		repositories, config.FetchInputs{MaxPages: 1}, config.Filters{}, nil,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("Expected 1 PR, got %d", len(prs))
+	}
+	if prs[0].HasReviewInfo() {
+		t.Errorf("Expected PR to have no review info")
+	}
+	expectedError := "error fetching reviews for pull request owner/repo#1: connection reset"
+	if prs[0].ReviewsFetchError.Error() != expectedError {
+		t.Errorf("Expected error '%s', got '%v'", expectedError, prs[0].ReviewsFetchError)
+	}
+}
+
+func TestFetchOpenPRsSize(t *testing.T) {
+	newService := func() *pagingPullRequestsService {
+		return &pagingPullRequestsService{
+			prs: []*github.PullRequest{{
+				Number:       github.Ptr(1),
+				User:         &github.User{Login: github.Ptr("author")},
+				Additions:    github.Ptr(30),
+				Deletions:    github.Ptr(20),
+				ChangedFiles: github.Ptr(3),
+			}},
+		}
+	}
+	repositories := []config.Repository{{Path: "owner/repo", Owner: "owner", Name: "repo"}}
+
+	t.Run("size is not fetched by default", func(t *testing.T) {
+		service := newService()
+		prs, err := githubclient.NewClient(service, service, service, service).FetchOpenPRs(
+			repositories, config.FetchInputs{MaxPages: 1}, config.Filters{}, nil,
+		)
+		if err != nil || len(prs) != 1 {
+			t.Fatalf("Expected 1 PR and no error, got %d PRs and error: %v", len(prs), err)
+		}
+		if prs[0].Size != nil || service.getCount != 0 {
+			t.Errorf("Expected no size and no Get requests, got %+v and %d requests", prs[0].Size, service.getCount)
+		}
+	})
+
+	t.Run("size is fetched", func(t *testing.T) {
+		service := newService()
+		prs, err := githubclient.NewClient(service, service, service, service).FetchOpenPRs(
+			repositories, config.FetchInputs{MaxPages: 1, FetchSize: true}, config.Filters{}, nil,
+		)
+		if err != nil || len(prs) != 1 {
+			t.Fatalf("Expected 1 PR and no error, got %d PRs and error: %v", len(prs), err)
+		}
+		expected := githubclient.PRSize{Additions: 30, Deletions: 20, ChangedFiles: 3}
+		if prs[0].Size == nil || *prs[0].Size != expected {
+			t.Errorf("Expected size %+v, got %+v", expected, prs[0].Size)
+		}
+		if prs[0].Size.LinesChanged() != 50 {
+			t.Errorf("Expected 50 lines changed, got %d", prs[0].Size.LinesChanged())
+		}
+	})
+
+	t.Run("size is unknown if details cannot be fetched", func(t *testing.T) {
+		service := newService()
+		service.getErr = errors.New("connection reset")
+		prs, err := githubclient.NewClient(service, service, service, service).FetchOpenPRs(
+			repositories, config.FetchInputs{MaxPages: 1, FetchSize: true}, config.Filters{}, nil,
+		)
+		if err != nil || len(prs) != 1 {
+			t.Fatalf("Expected 1 PR and no error, got %d PRs and error: %v", len(prs), err)
+		}
+		if prs[0].Size != nil {
+			t.Errorf("Expected size to be nil, got %+v", prs[0].Size)
+		}
+	})
+}
+
+func TestFetchOpenPRsMergeable(t *testing.T) {
+	repositories := []config.Repository{{Path: "owner/repo", Owner: "owner", Name: "repo"}}
+
+	testCases := []struct {
+		name                 string
+		mergeable            *bool
+		unknownMergeableGets int
+		expectedMergeable    *bool
+		fetchMergeability    bool
+		fetchSize            bool
+		expectedGetCount     int
+	}{
+		{
+			name:              "mergeable",
+			mergeable:         github.Ptr(true),
+			fetchMergeability: true,
+			expectedMergeable: github.Ptr(true),
+			expectedGetCount:  1,
+		},
+		{
+			name:              "conflicts",
+			mergeable:         github.Ptr(false),
+			fetchMergeability: true,
+			expectedMergeable: github.Ptr(false),
+			expectedGetCount:  1,
+		},
+		{
+			name:                 "computed on retry",
+			mergeable:            github.Ptr(false),
+			unknownMergeableGets: 2,
+			fetchMergeability:    true,
+			expectedMergeable:    github.Ptr(false),
+			expectedGetCount:     3,
+		},
+		{
+			name:                 "unknown after retries",
+			unknownMergeableGets: 10,
+			fetchMergeability:    true,
+			expectedMergeable:    nil,
+			expectedGetCount:     4,
+		},
+		{
+			name:                 "not retried if only the size is needed",
+			mergeable:            github.Ptr(false),
+			unknownMergeableGets: 2,
+			fetchSize:            true,
+			expectedMergeable:    nil,
+			expectedGetCount:     1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &pagingPullRequestsService{
+				prs: []*github.PullRequest{{
+					Number:    github.Ptr(1),
+					User:      &github.User{Login: github.Ptr("author")},
+					Mergeable: tc.mergeable,
+				}},
+				unknownMergeableGets: tc.unknownMergeableGets,
+			}
+			client := githubclient.NewClient(service, service, service, service)
+			githubclient.SetMergeableRetryDelay(client, 0)
+
+			fetchInputs := config.FetchInputs{
+				MaxPages: 1, FetchMergeability: tc.fetchMergeability, FetchSize: tc.fetchSize,
+			}
+			prs, err := client.FetchOpenPRs(repositories, fetchInputs, config.Filters{}, nil)
+			if err != nil || len(prs) != 1 {
+				t.Fatalf("Expected 1 PR and no error, got %d PRs and error: %v", len(prs), err)
+			}
+			if !reflect.DeepEqual(prs[0].Mergeable, tc.expectedMergeable) {
+				t.Errorf("Expected mergeable %v, got %v", tc.expectedMergeable, prs[0].Mergeable)
+			}
+			if prs[0].HasMergeConflicts() != (tc.expectedMergeable != nil && !*tc.expectedMergeable) {
+				t.Errorf("Unexpected HasMergeConflicts: %v", prs[0].HasMergeConflicts())
+			}
+			if service.getCount != tc.expectedGetCount {
+				t.Errorf("Expected %d Get requests, got %d", tc.expectedGetCount, service.getCount)
+			}
+		})
+	}
+}
+
+func TestFetchOpenPRsTimelineEvents(t *testing.T) {
+	repositories := []config.Repository{{Path: "owner/repo", Owner: "owner", Name: "repo"}}
+	day := func(d int) *github.Timestamp {
+		return &github.Timestamp{Time: time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC)}
+	}
+	service := &pagingPullRequestsService{
+		prs: []*github.PullRequest{{Number: github.Ptr(1), User: &github.User{Login: github.Ptr("author")}}},
+		timelineEvents: []*github.Timeline{
+			{Event: github.Ptr("review_requested"), CreatedAt: day(1)},
+			{Event: github.Ptr("ready_for_review"), CreatedAt: day(2)},
+			{Event: github.Ptr("commented"), CreatedAt: day(3)},
+			{Event: github.Ptr("convert_to_draft"), CreatedAt: day(4)},
+			{Event: github.Ptr("ready_for_review"), CreatedAt: day(5)},
+			{Event: github.Ptr("review_requested"), CreatedAt: day(6)},
+		},
+	}
+	client := githubclient.NewClient(service, service, service, service)
+
+	t.Run("not fetched by default", func(t *testing.T) {
+		prs, err := client.FetchOpenPRs(repositories, config.FetchInputs{MaxPages: 10}, config.Filters{}, nil)
+		if err != nil || len(prs) != 1 {
+			t.Fatalf("Expected 1 PR and no error, got %d PRs and error: %v", len(prs), err)
+		}
+		if prs[0].ReadyForReviewAt != nil || prs[0].LastReviewRequestedAt != nil {
+			t.Errorf("Expected no timeline times, got %v and %v", prs[0].ReadyForReviewAt, prs[0].LastReviewRequestedAt)
+		}
+	})
+
+	t.Run("latest events", func(t *testing.T) {
+		fetchInputs := config.FetchInputs{MaxPages: 10, FetchTimelineEvents: true}
+		prs, err := client.FetchOpenPRs(repositories, fetchInputs, config.Filters{}, nil)
+		if err != nil || len(prs) != 1 {
+			t.Fatalf("Expected 1 PR and no error, got %d PRs and error: %v", len(prs), err)
+		}
+		if prs[0].ReadyForReviewAt == nil || !prs[0].ReadyForReviewAt.Equal(day(5).Time) {
+			t.Errorf("Expected ready for review at %v, got %v", day(5).Time, prs[0].ReadyForReviewAt)
+		}
+		if prs[0].LastReviewRequestedAt == nil || !prs[0].LastReviewRequestedAt.Equal(day(6).Time) {
+			t.Errorf("Expected last review requested at %v, got %v", day(6).Time, prs[0].LastReviewRequestedAt)
+		}
+	})
+}
+
+func TestFetchOpenPRsCIStatus(t *testing.T) {
+	checkRun := func(status string, conclusion string) *github.CheckRun {
+		return &github.CheckRun{Status: github.Ptr(status), Conclusion: github.Ptr(conclusion)}
+	}
+	combinedStatus := func(state string) *github.CombinedStatus {
+		return &github.CombinedStatus{State: github.Ptr(state), TotalCount: github.Ptr(1)}
+	}
+	repositories := []config.Repository{{Path: "owner/repo", Owner: "owner", Name: "repo"}}
+
+	testCases := []struct {
+		name           string
+		headSHA        string
+		combinedStatus *github.CombinedStatus
+		checkRuns      []*github.CheckRun
+		timelineEvents []*github.Timeline
+		expected       githubclient.CIStatus
+	}{
+		{
+			name:      "passing check runs",
+			headSHA:   "abc",
+			checkRuns: []*github.CheckRun{checkRun("completed", "success"), checkRun("completed", "neutral")},
+			expected:  githubclient.CIStatusPassing,
+		},
+		{
+			name:      "failing check run on a later page",
+			headSHA:   "abc",
+			checkRuns: []*github.CheckRun{checkRun("completed", "success"), checkRun("completed", "timed_out")},
+			expected:  githubclient.CIStatusFailing,
+		},
+		{
+			name:      "pending check run",
+			headSHA:   "abc",
+			checkRuns: []*github.CheckRun{checkRun("completed", "success"), checkRun("queued", "")},
+			expected:  githubclient.CIStatusPending,
+		},
+		{
+			name:           "failing commit status",
+			headSHA:        "abc",
+			combinedStatus: combinedStatus("error"),
+			checkRuns:      []*github.CheckRun{checkRun("completed", "success")},
+			expected:       githubclient.CIStatusFailing,
+		},
+		{
+			// the combined state is "pending" when there are no commit statuses
+			name:           "no commit statuses or check runs",
+			headSHA:        "abc",
+			combinedStatus: &github.CombinedStatus{State: github.Ptr("pending"), TotalCount: github.Ptr(0)},
+			expected:       githubclient.CIStatusNone,
+		},
+		{
+			name:     "unknown head commit",
+			expected: githubclient.CIStatusUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &pagingPullRequestsService{
+				prs: []*github.PullRequest{{
+					Number: github.Ptr(1),
+					User:   &github.User{Login: github.Ptr("author")},
+					Head:   &github.PullRequestBranch{SHA: github.Ptr(tc.headSHA)},
+				}},
+				combinedStatus: tc.combinedStatus,
+				checkRuns:      tc.checkRuns,
+			}
+			prs, err := githubclient.NewClient(service, service, service, service).FetchOpenPRs(
+				repositories, config.FetchInputs{MaxPages: 10, FetchCIStatus: true}, config.Filters{}, nil,
+			)
+			if err != nil || len(prs) != 1 {
+				t.Fatalf("Expected 1 PR and no error, got %d PRs and error: %v", len(prs), err)
+			}
+			if prs[0].CIStatus != tc.expected {
+				t.Errorf("Expected CI status '%s', got '%s'", tc.expected, prs[0].CIStatus)
+			}
+		})
+	}
+
+	t.Run("not fetched by default", func(t *testing.T) {
+		service := &pagingPullRequestsService{
+			prs: []*github.PullRequest{{
+				Number: github.Ptr(1),
+				User:   &github.User{Login: github.Ptr("author")},
+				Head:   &github.PullRequestBranch{SHA: github.Ptr("sha")},
+			}},
+			combinedStatus: combinedStatus("success"),
+		}
+		prs, err := githubclient.NewClient(service, service, service, service).FetchOpenPRs(
+			repositories, config.FetchInputs{MaxPages: 10}, config.Filters{}, nil,
+		)
+		if err != nil || len(prs) != 1 {
+			t.Fatalf("Expected 1 PR and no error, got %d PRs and error: %v", len(prs), err)
+		}
+		if prs[0].CIStatus != githubclient.CIStatusUnknown {
+			t.Errorf("Expected CI status to be unknown, got '%s'", prs[0].CIStatus)
+		}
+	})
+}
+
+func TestFetchOpenPRsLatestReviewStates(t *testing.T) {
+	review := func(login string, state string, hoursAgo int) *github.PullRequestReview {
+		return &github.PullRequestReview{
+			User:        &github.User{Login: github.Ptr(login)},
+			State:       github.Ptr(state),
+			SubmittedAt: &github.Timestamp{Time: time.Now().Add(-time.Duration(hoursAgo) * time.Hour)},
+		}
+	}
+	testCases := []struct {
+		name          string
+		reviews       []*github.PullRequestReview
+		expectedState githubclient.ReviewState
+	}{
+		{
+			name:          "approval",
+			reviews:       []*github.PullRequestReview{review("alice", "APPROVED", 1)},
+			expectedState: githubclient.ReviewStateApproved,
+		},
+		{
+			name: "changes requested after approval",
+			reviews: []*github.PullRequestReview{
+				review("alice", "APPROVED", 3), review("alice", "CHANGES_REQUESTED", 2),
+			},
+			expectedState: githubclient.ReviewStateChangesRequested,
+		},
+		{
+			name: "comment after changes requested",
+			reviews: []*github.PullRequestReview{
+				review("alice", "CHANGES_REQUESTED", 3), review("alice", "COMMENTED", 2),
+			},
+			expectedState: githubclient.ReviewStateChangesRequested,
+		},
+		{
+			name: "approval after changes requested (listed out of order)",
+			reviews: []*github.PullRequestReview{
+				review("alice", "APPROVED", 1), review("alice", "CHANGES_REQUESTED", 2),
+			},
+			expectedState: githubclient.ReviewStateApproved,
+		},
+		{
+			name: "dismissed review",
+			reviews: []*github.PullRequestReview{
+				review("alice", "COMMENTED", 3), review("alice", "DISMISSED", 2), review("alice", "PENDING", 1),
+			},
+			expectedState: githubclient.ReviewStateDismissed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &pagingPullRequestsService{
+				prs: []*github.PullRequest{{
+					Number: github.Ptr(1),
+					User:   &github.User{Login: github.Ptr("author")},
+				}},
+				reviews: tc.reviews,
+			}
+			repositories := []config.Repository{{Path: "owner/repo", Owner: "owner", Name: "repo"}}
+			prs, err := githubclient.NewClient(service, service, service, service).FetchOpenPRs(
+				repositories, config.FetchInputs{MaxPages: 10}, config.Filters{}, nil,
+			)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			latestReviews := prs[0].LatestReviews
+			if len(latestReviews) != 1 {
+				t.Fatalf("Expected exactly one latest review, got %d", len(latestReviews))
+			}
+			if latestReviews[0].State != tc.expectedState {
+				t.Errorf("Expected state %s, got %s", tc.expectedState, latestReviews[0].State)
+			}
+			reviewerCount := len(prs[0].ApprovedByUsers) +
+				len(prs[0].ChangesRequestedByUsers) +
+				len(prs[0].CommentedByUsers)
+			if reviewerCount != 1 {
+				t.Errorf("Expected the reviewer to be in exactly one reviewer list, was in %d", reviewerCount)
+			}
+		})
+	}
+}
+
+func TestFetchOpenPRsStaleApprovals(t *testing.T) {
+	approval := func(login string, commitID string) *github.PullRequestReview {
+		return &github.PullRequestReview{
+			User:     &github.User{Login: github.Ptr(login)},
+			State:    github.Ptr("APPROVED"),
+			CommitID: github.Ptr(commitID),
+		}
+	}
+	service := &pagingPullRequestsService{
+		prs: []*github.PullRequest{{
+			Number: github.Ptr(1),
+			User:   &github.User{Login: github.Ptr("author")},
+			Head:   &github.PullRequestBranch{SHA: github.Ptr("new-sha")},
+		}},
+		reviews: []*github.PullRequestReview{approval("alice", "old-sha"), approval("bob", "new-sha")},
+	}
+	repositories := []config.Repository{{Path: "owner/repo", Owner: "owner", Name: "repo"}}
+
+	prs, err := githubclient.NewClient(service, service, service, service).FetchOpenPRs(
+		repositories, config.FetchInputs{MaxPages: 10}, config.Filters{}, nil,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	pr := prs[0]
+	if len(pr.ApprovedByUsers) != 1 || pr.ApprovedByUsers[0].Login != "bob" {
+		t.Errorf("Expected only bob to have approved the head commit, got %v", pr.ApprovedByUsers)
+	}
+	if len(pr.StaleApprovedByUsers) != 1 || pr.StaleApprovedByUsers[0].Login != "alice" {
+		t.Errorf("Expected alice to have approved an older revision, got %v", pr.StaleApprovedByUsers)
+	}
+}
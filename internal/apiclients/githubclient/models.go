@@ -4,9 +4,12 @@ import (
 	"cmp"
 	"log"
 	"slices"
+	"time"
 
 	"github.com/google/go-github/v72/github"
 	"github.com/hellej/pr-slack-reminder-action/internal/config"
+	"github.com/hellej/pr-slack-reminder-action/internal/config/expression"
+	"github.com/hellej/pr-slack-reminder-action/internal/workcalendar"
 )
 
 type PRsOfRepoResult struct {
@@ -23,46 +26,209 @@ func (r PRsOfRepoResult) GetPRCount() int {
 	return 0
 }
 
+type ReviewState string
+
+const (
+	ReviewStateApproved         ReviewState = "APPROVED"
+	ReviewStateChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewStateCommented        ReviewState = "COMMENTED"
+	ReviewStateDismissed        ReviewState = "DISMISSED"
+)
+
+var effectiveReviewStates = []ReviewState{
+	ReviewStateApproved, ReviewStateChangesRequested, ReviewStateCommented, ReviewStateDismissed,
+}
+
+// Returns true for states that stay in effect until the reviewer submits another verdict.
+func (s ReviewState) isVerdict() bool {
+	return s == ReviewStateApproved || s == ReviewStateChangesRequested
+}
+
+type Review struct {
+	Reviewer    Collaborator
+	State       ReviewState
+	CommitID    string // SHA of the commit the review was submitted on
+	SubmittedAt time.Time
+}
+
+// Returns true if the review was submitted on an older commit than the given head commit.
+func (r Review) IsStale(headSHA string) bool {
+	return r.CommitID != "" && headSHA != "" && r.CommitID != headSHA
+}
+
 type PR struct {
+	// The full details of the PR if they could be fetched, otherwise the PR as listed
+	// (without e.g. size and mergeability)
 	*github.PullRequest
+	// Repository owner (user or organization)
+	Owner string
 	// Repository name (just the name, no owner)
-	Repository       string
-	Author           Collaborator
-	CommentedByUsers []Collaborator // reviewers who commented the PR but did not approve it
-	ApprovedByUsers  []Collaborator
+	Repository string
+	Author     Collaborator
+	// The latest effective review of each reviewer
+	LatestReviews []Review
+	// Reviewers whose latest review is a comment or a dismissed review
+	CommentedByUsers []Collaborator
+	// Reviewers whose latest review approves the current head commit of the PR
+	ApprovedByUsers []Collaborator
+	// Reviewers whose latest review approves an older commit than the current head
+	StaleApprovedByUsers    []Collaborator
+	ChangesRequestedByUsers []Collaborator
+	// Users and teams whose review has been requested but who have not reviewed yet
+	RequestedReviewers []Collaborator
+	RequestedTeams     []Team
+	// Set if the reviews of the PR could not be fetched (reviewer lists are then empty)
+	ReviewsFetchError error
+	// Nil if the details of the PR were not fetched (see config.FetchInputs) or could not be
+	Size *PRSize
+	// The CI status of the head commit of the PR (unknown if not fetched, see config.FetchInputs)
+	CIStatus CIStatus
+	// When the PR was last marked ready for review (nil if it was opened as ready for review
+	// or if the timeline events were not fetched)
+	ReadyForReviewAt *time.Time
+	// When a review was last requested (nil if never or if the timeline events were not fetched)
+	LastReviewRequestedAt *time.Time
 }
 
-func (pr PR) isMatch(filters config.Filters) bool {
-	if len(filters.LabelsIgnore) > 0 {
-		if slices.ContainsFunc(pr.Labels, func(l *github.Label) bool {
-			return slices.Contains(filters.LabelsIgnore, l.GetName())
-		}) {
-			return false
-		}
+type PRSize struct {
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+}
+
+// Returns the number of lines changed (additions + deletions).
+func (s PRSize) LinesChanged() int {
+	return s.Additions + s.Deletions
+}
+
+// Returns the full path of the repository of the PR, e.g. "owner/repo".
+func (pr PR) GetRepositoryPath() string {
+	return pr.Owner + "/" + pr.Repository
+}
+
+// Returns the time from which the age of the PR is measured. Falls back to the creation time
+// if the PR has not been marked ready for review or had reviews requested.
+func (pr PR) GetAgeStartTime(ageBasis config.AgeBasis) time.Time {
+	switch {
+	case ageBasis == config.AgeBasisUpdated:
+		return pr.GetUpdatedAt().Time
+	case ageBasis == config.AgeBasisReadyForReview && pr.ReadyForReviewAt != nil:
+		return *pr.ReadyForReviewAt
+	case ageBasis == config.AgeBasisReviewRequested && pr.LastReviewRequestedAt != nil:
+		return *pr.LastReviewRequestedAt
 	}
-	if len(filters.AuthorsIgnore) > 0 {
-		if slices.Contains(filters.AuthorsIgnore, pr.Author.Login) {
-			return false
-		}
+	return pr.GetCreatedAt().Time
+}
+
+// Returns the age of the PR measured from the point in time defined by the age basis
+// (counting only working hours if a business hours calendar is given).
+func (pr PR) GetAge(ageBasis config.AgeBasis, businessHoursCalendar *workcalendar.Calendar) time.Duration {
+	if businessHoursCalendar != nil {
+		return businessHoursCalendar.WorkingTimeBetween(pr.GetAgeStartTime(ageBasis), time.Now())
+	}
+	return time.Since(pr.GetAgeStartTime(ageBasis))
+}
+
+// Returns true if the PR is known to have merge conflicts with its base branch (false if the
+// mergeability is unknown).
+func (pr PR) HasMergeConflicts() bool {
+	return pr.Mergeable != nil && !*pr.Mergeable
+}
+
+// Returns false if the reviews of the PR could not be fetched, in which case
+// the PR should not be presented as unreviewed.
+func (pr PR) HasReviewInfo() bool {
+	return pr.ReviewsFetchError == nil
+}
+
+func (pr PR) isMatch(filters config.Filters, expressionFields expression.Fields) bool {
+	if filters.Drafts == config.DraftsExclude && pr.GetDraft() {
+		return false
+	}
+	if filters.Drafts == config.DraftsOnly && !pr.GetDraft() {
+		return false
+	}
+	if filters.BaseBranchesIgnore.MatchesAny(pr.GetBase().GetRef()) {
+		return false
+	}
+	if filters.HeadBranchesIgnore.MatchesAny(pr.GetHead().GetRef()) {
+		return false
+	}
+	if len(filters.BaseBranches) > 0 && !filters.BaseBranches.MatchesAny(pr.GetBase().GetRef()) {
+		return false
+	}
+	if len(filters.HeadBranches) > 0 && !filters.HeadBranches.MatchesAny(pr.GetHead().GetRef()) {
+		return false
+	}
+	if slices.ContainsFunc(pr.Labels, func(l *github.Label) bool {
+		return filters.LabelsIgnore.MatchesAny(l.GetName())
+	}) {
+		return false
+	}
+	if filters.AuthorsIgnore.MatchesAny(pr.Author.Login) {
+		return false
 	}
 	if len(filters.Labels) > 0 {
 		if !slices.ContainsFunc(pr.Labels, func(l *github.Label) bool {
-			return slices.Contains(filters.Labels, l.GetName())
+			return filters.Labels.MatchesAny(l.GetName())
 		}) {
 			return false
 		}
 	}
 	if len(filters.Authors) > 0 {
-		if !slices.Contains(filters.Authors, pr.Author.Login) {
+		if !filters.Authors.MatchesAny(pr.Author.Login) {
+			return false
+		}
+	}
+	if filters.CI != "" && string(pr.CIStatus) != string(filters.CI) {
+		return false
+	}
+	if filters.MinSize != nil || filters.MaxSize != nil {
+		if pr.Size == nil {
+			log.Printf("Excluding PR %s#%d as its size is unknown", pr.GetRepositoryPath(), pr.GetNumber())
+			return false
+		}
+		if filters.MinSize != nil && pr.Size.LinesChanged() < *filters.MinSize {
+			return false
+		}
+		if filters.MaxSize != nil && pr.Size.LinesChanged() > *filters.MaxSize {
 			return false
 		}
 	}
-	return true
+	return filters.MatchesExpression(expressionFields)
+}
+
+func (pr PR) getExpressionFields(fetchInputs config.FetchInputs) expression.Fields {
+	labels := make([]string, len(pr.Labels))
+	for i, label := range pr.Labels {
+		labels[i] = label.GetName()
+	}
+	fields := expression.Fields{
+		Author:     pr.Author.Login,
+		Repository: pr.Repository,
+		BaseBranch: pr.GetBase().GetRef(),
+		HeadBranch: pr.GetHead().GetRef(),
+		Labels:     labels,
+		Draft:      pr.GetDraft(),
+		AgeHours:   pr.GetAge(fetchInputs.AgeBasis, fetchInputs.BusinessHoursCalendar).Hours(),
+		CIStatus:   string(pr.CIStatus),
+	}
+	if pr.Size != nil {
+		linesChanged := pr.Size.LinesChanged()
+		fields.Size, fields.Files = &linesChanged, &pr.Size.ChangedFiles
+	}
+	return fields
 }
 
 type FetchReviewsResult struct {
-	pr      *github.PullRequest
-	reviews []*github.PullRequestReview
+	pr *github.PullRequest
+	// Full details of the PR, nil if they were not needed or could not be fetched
+	details  *github.PullRequest
+	reviews  []*github.PullRequestReview
+	ciStatus CIStatus
+	// Only fetched if needed (see config.AgeBasis)
+	timelineTimes timelineTimes
+	owner         string
 	// Repository name (just the name, no owner)
 	repository string
 	err        error
@@ -101,45 +267,146 @@ func (c Collaborator) GetGitHubName() string {
 	return cmp.Or(c.Name, c.Login)
 }
 
+type Team struct {
+	Slug string // e.g. "platform-team"
+	Name string
+}
+
+func NewTeam(team *github.Team) Team {
+	return Team{
+		Slug: team.GetSlug(),
+		Name: team.GetName(),
+	}
+}
+
+func getRequestedReviewers(pr *github.PullRequest) ([]Collaborator, []Team) {
+	reviewers := make([]Collaborator, 0, len(pr.RequestedReviewers))
+	for _, user := range pr.RequestedReviewers {
+		reviewers = append(reviewers, NewCollaboratorFromUser(user))
+	}
+	teams := make([]Team, 0, len(pr.RequestedTeams))
+	for _, team := range pr.RequestedTeams {
+		teams = append(teams, NewTeam(team))
+	}
+	return reviewers, teams
+}
+
+// Returns the full details of the PR if available, otherwise the PR as listed.
+func (r FetchReviewsResult) getPullRequest() *github.PullRequest {
+	if r.details != nil {
+		return r.details
+	}
+	return r.pr
+}
+
+func (r FetchReviewsResult) getSize() *PRSize {
+	if r.details == nil {
+		return nil
+	}
+	return &PRSize{
+		Additions:    r.details.GetAdditions(),
+		Deletions:    r.details.GetDeletions(),
+		ChangedFiles: r.details.GetChangedFiles(),
+	}
+}
+
 func (r FetchReviewsResult) asPR() PR {
+	pullRequest := r.getPullRequest()
+	requestedReviewers, requestedTeams := getRequestedReviewers(pullRequest)
+	if r.err != nil {
+		return PR{
+			PullRequest:             pullRequest,
+			Owner:                   r.owner,
+			Repository:              r.repository,
+			Author:                  NewCollaboratorFromUser(r.pr.GetUser()),
+			CommentedByUsers:        []Collaborator{},
+			ApprovedByUsers:         []Collaborator{},
+			StaleApprovedByUsers:    []Collaborator{},
+			ChangesRequestedByUsers: []Collaborator{},
+			RequestedReviewers:      requestedReviewers,
+			RequestedTeams:          requestedTeams,
+			ReviewsFetchError:       r.err,
+			Size:                    r.getSize(),
+			CIStatus:                r.ciStatus,
+			ReadyForReviewAt:        r.timelineTimes.readyForReviewAt,
+			LastReviewRequestedAt:   r.timelineTimes.lastReviewRequestedAt,
+		}
+	}
+
+	latestReviews := getLatestReviews(r.reviews)
 	approvedByUsers := []Collaborator{}
+	staleApprovedByUsers := []Collaborator{}
+	changesRequestedByUsers := []Collaborator{}
 	commentedByUsers := []Collaborator{}
 
-	for _, review := range r.reviews {
-		login := review.GetUser().GetLogin()
-		if login == "" {
-			continue
-		}
-		if review.GetState() == "APPROVED" {
-			if !slices.ContainsFunc(approvedByUsers, func(c Collaborator) bool {
-				return c.Login == login
-			}) {
-				approvedByUsers = append(
-					approvedByUsers, NewCollaboratorFromUser(review.GetUser()),
-				)
-			}
-
-		} else {
-			if !slices.ContainsFunc(commentedByUsers, func(c Collaborator) bool {
-				return c.Login == login
-			}) && !slices.ContainsFunc(approvedByUsers, func(c Collaborator) bool {
-				// Only add to commentedByUsers if the user has not already approved
-				return c.Login == login
-			}) {
-				commentedByUsers = append(
-					commentedByUsers, NewCollaboratorFromUser(review.GetUser()),
-				)
-			}
+	for _, review := range latestReviews {
+		switch {
+		case review.State == ReviewStateApproved && review.IsStale(pullRequest.GetHead().GetSHA()):
+			staleApprovedByUsers = append(staleApprovedByUsers, review.Reviewer)
+		case review.State == ReviewStateApproved:
+			approvedByUsers = append(approvedByUsers, review.Reviewer)
+		case review.State == ReviewStateChangesRequested:
+			changesRequestedByUsers = append(changesRequestedByUsers, review.Reviewer)
+		default:
+			commentedByUsers = append(commentedByUsers, review.Reviewer)
 		}
 	}
 
 	return PR{
-		PullRequest:      r.pr,
-		Repository:       r.repository,
-		Author:           NewCollaboratorFromUser(r.pr.GetUser()),
-		CommentedByUsers: commentedByUsers,
-		ApprovedByUsers:  approvedByUsers,
+		PullRequest:             pullRequest,
+		Owner:                   r.owner,
+		Repository:              r.repository,
+		Author:                  NewCollaboratorFromUser(r.pr.GetUser()),
+		LatestReviews:           latestReviews,
+		CommentedByUsers:        commentedByUsers,
+		ApprovedByUsers:         approvedByUsers,
+		StaleApprovedByUsers:    staleApprovedByUsers,
+		ChangesRequestedByUsers: changesRequestedByUsers,
+		RequestedReviewers:      requestedReviewers,
+		RequestedTeams:          requestedTeams,
+		Size:                    r.getSize(),
+		CIStatus:                r.ciStatus,
+		ReadyForReviewAt:        r.timelineTimes.readyForReviewAt,
+		LastReviewRequestedAt:   r.timelineTimes.lastReviewRequestedAt,
+	}
+}
+
+// Resolves the latest effective review state of each reviewer. Comments submitted after
+// an approval or a change request do not override it (as on GitHub), whereas a later
+// approval, change request or dismissal does. Pending reviews are ignored.
+// Reviewers are returned in the order of their first review.
+func getLatestReviews(reviews []*github.PullRequestReview) []Review {
+	sortedReviews := slices.Clone(reviews)
+	slices.SortStableFunc(sortedReviews, func(a, b *github.PullRequestReview) int {
+		return a.GetSubmittedAt().Time.Compare(b.GetSubmittedAt().Time)
+	})
+
+	latestReviews := []Review{}
+	for _, review := range sortedReviews {
+		login := review.GetUser().GetLogin()
+		state := ReviewState(review.GetState())
+		if login == "" || !slices.Contains(effectiveReviewStates, state) {
+			continue
+		}
+		latestReview := Review{
+			Reviewer:    NewCollaboratorFromUser(review.GetUser()),
+			State:       state,
+			CommitID:    review.GetCommitID(),
+			SubmittedAt: review.GetSubmittedAt().Time,
+		}
+		idx := slices.IndexFunc(latestReviews, func(r Review) bool {
+			return r.Reviewer.Login == login
+		})
+		switch {
+		case idx == -1:
+			latestReviews = append(latestReviews, latestReview)
+		case state == ReviewStateCommented && latestReviews[idx].State.isVerdict():
+			continue
+		default:
+			latestReviews[idx] = latestReview
+		}
 	}
+	return latestReviews
 }
 
 type OwnerAndRepo struct {
@@ -0,0 +1,40 @@
+package githubclient
+
+import "github.com/google/go-github/v72/github"
+
+const pageSize = 100
+
+type pagedResult[T any] struct {
+	items []T
+	// Response of the last fetched page (nil if no page was fetched)
+	response *github.Response
+	// True if there were still more pages left when maxPages was reached
+	limitReached bool
+}
+
+// Calls fetchPage for consecutive pages (following github.Response.NextPage) until
+// there are no more pages or maxPages pages have been fetched.
+func fetchAllPages[T any](
+	maxPages int,
+	fetchPage func(listOptions github.ListOptions) ([]T, *github.Response, error),
+) (pagedResult[T], error) {
+	result := pagedResult[T]{}
+	listOptions := github.ListOptions{PerPage: pageSize}
+
+	for pageCount := 1; ; pageCount++ {
+		items, response, err := fetchPage(listOptions)
+		result.response = response
+		if err != nil {
+			return result, err
+		}
+		result.items = append(result.items, items...)
+		if response == nil || response.NextPage == 0 {
+			return result, nil
+		}
+		if pageCount >= maxPages {
+			result.limitReached = true
+			return result, nil
+		}
+		listOptions.Page = response.NextPage
+	}
+}
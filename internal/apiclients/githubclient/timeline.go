@@ -0,0 +1,56 @@
+package githubclient
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+)
+
+// Times of the timeline events of a PR that are used to measure its age
+type timelineTimes struct {
+	readyForReviewAt      *time.Time
+	lastReviewRequestedAt *time.Time
+}
+
+// Returns empty times (and logs the error) if the timeline events cannot be fetched.
+func (c *client) fetchTimelineTimes(owner string, repo string, pr *github.PullRequest, maxPages int) timelineTimes {
+	result, err := fetchAllPages(maxPages, func(listOptions github.ListOptions) (
+		[]*github.Timeline, *github.Response, error,
+	) {
+		return c.issuesService.ListIssueTimeline(context.Background(), owner, repo, pr.GetNumber(), &listOptions)
+	})
+	if err != nil {
+		log.Printf("Unable to fetch timeline events for pull request %s/%s#%d: %v", owner, repo, pr.GetNumber(), err)
+		return timelineTimes{}
+	}
+	if result.limitReached {
+		log.Printf(
+			"Reached the limit of %d pages when fetching timeline events for pull request %s/%s#%d, some events may be missing",
+			maxPages, owner, repo, pr.GetNumber(),
+		)
+	}
+	return getTimelineTimes(result.items)
+}
+
+func getTimelineTimes(events []*github.Timeline) timelineTimes {
+	times := timelineTimes{}
+	for _, event := range events {
+		createdAt := event.GetCreatedAt().Time
+		switch event.GetEvent() {
+		case "ready_for_review":
+			times.readyForReviewAt = latest(times.readyForReviewAt, createdAt)
+		case "review_requested":
+			times.lastReviewRequestedAt = latest(times.lastReviewRequestedAt, createdAt)
+		}
+	}
+	return times
+}
+
+func latest(current *time.Time, candidate time.Time) *time.Time {
+	if current == nil || candidate.After(*current) {
+		return &candidate
+	}
+	return current
+}
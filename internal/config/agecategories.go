@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/config/utilities"
+)
+
+// PRs that are at least MinAgeHours old (and younger than the next category) are listed
+// under the heading of the category.
+type AgeCategory struct {
+	MinAgeHours int
+	Heading     string
+}
+
+// Reads the age categories from a list input where each line is in format
+// "<min age in hours>: <heading>", e.g. "0: New PRs", "24: 1-3 days old" and "72: Old PRs".
+// The categories are returned in ascending order of age.
+func GetAgeCategoriesFromInput(input string) ([]AgeCategory, error) {
+	categories := []AgeCategory{}
+	for _, line := range utilities.GetInputList(input) {
+		if line == "" {
+			continue
+		}
+		rawHours, heading, found := strings.Cut(line, ":")
+		heading = strings.TrimSpace(heading)
+		if !found || heading == "" {
+			return nil, fmt.Errorf(
+				"invalid age category in input %s: %s (expected format \"<min age in hours>: <heading>\")",
+				input, line,
+			)
+		}
+		hours, err := strconv.Atoi(strings.TrimSpace(rawHours))
+		if err != nil || hours < 0 {
+			return nil, fmt.Errorf(
+				"invalid age category in input %s: %s (min age must be a non-negative integer)", input, line,
+			)
+		}
+		if slices.ContainsFunc(categories, func(c AgeCategory) bool { return c.MinAgeHours == hours }) {
+			return nil, fmt.Errorf("duplicate min age %d in input %s", hours, input)
+		}
+		categories = append(categories, AgeCategory{MinAgeHours: hours, Heading: heading})
+	}
+	if len(categories) == 0 {
+		return categories, nil
+	}
+	slices.SortFunc(categories, func(a, b AgeCategory) int {
+		return a.MinAgeHours - b.MinAgeHours
+	})
+	if categories[0].MinAgeHours != 0 {
+		return nil, fmt.Errorf("input %s must include a category for the newest PRs (min age 0)", input)
+	}
+	return categories, nil
+}
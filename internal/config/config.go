@@ -1,34 +1,171 @@
 package config
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"log"
 
 	"github.com/hellej/pr-slack-reminder-action/internal/config/utilities"
+	"github.com/hellej/pr-slack-reminder-action/internal/workcalendar"
 )
 
 const (
-	EnvGithubRepository              string = "GITHUB_REPOSITORY"
-	InputGithubRepositories          string = "github-repositories"
-	InputGithubToken                 string = "github-token"
-	InputSlackBotToken               string = "slack-bot-token"
-	InputSlackChannelName            string = "slack-channel-name"
-	InputSlackChannelID              string = "slack-channel-id"
-	InputSlackUserIdByGitHubUsername string = "github-user-slack-user-id-mapping"
-	InputNoPRsMessage                string = "no-prs-message"
-	InputMainListHeading             string = "main-list-heading"
-	InputOldPRsListHeading           string = "old-prs-list-heading"
-	InputOldPRThresholdHours         string = "old-pr-threshold-hours"
-	InputGlobalFilters               string = "filters"
-	InputRepositoryFilters           string = "repository-filters"
+	EnvGithubRepository               string = "GITHUB_REPOSITORY"
+	InputGithubRepositories           string = "github-repositories"
+	InputGithubToken                  string = "github-token"
+	InputSlackBotToken                string = "slack-bot-token"
+	InputSlackChannelName             string = "slack-channel-name"
+	InputSlackChannelID               string = "slack-channel-id"
+	InputSlackUserIdByGitHubUsername  string = "github-user-slack-user-id-mapping"
+	InputSlackUserGroupIdByGitHubTeam string = "github-team-slack-user-group-id-mapping"
+	InputNoPRsMessage                 string = "no-prs-message"
+	InputMainListHeading              string = "main-list-heading"
+	InputOldPRsListHeading            string = "old-prs-list-heading"
+	InputOldPRThresholdHours          string = "old-pr-threshold-hours"
+	InputAgeCategories                string = "age-categories"
+	InputAgeBasis                     string = "age-basis"
+	InputBusinessHoursAge             string = "business-hours-age"
+	InputWorkingCalendar              string = "working-calendar"
+	InputHolidaysICalPath             string = "holidays-ical-path"
+	InputSkipNonWorkingDays           string = "skip-non-working-days"
+	InputDraftPRsListHeading          string = "drafts-list-heading"
+	InputReadyToMergeListHeading      string = "ready-to-merge-list-heading"
+	InputReadyToMergeApprovals        string = "ready-to-merge-approvals"
+	InputNeedsRebaseListHeading       string = "needs-rebase-list-heading"
+	InputGlobalFilters                string = "filters"
+	InputRepositoryFilters            string = "repository-filters"
+	InputFiltersMergeStrategy         string = "repository-filters-merge-strategy"
+	InputGithubMaxPages               string = "github-max-pages"
+	InputContinueOnRepositoryErrors   string = "continue-on-repository-errors"
+	InputIgnoreStaleApprovals         string = "ignore-stale-approvals"
+	InputReviewerDisplay              string = "reviewer-display"
+	InputGroupBy                      string = "group-by"
+	InputSortBy                       string = "sort-by"
+	InputLabelGroups                  string = "label-groups"
+	InputUngroupedHeading             string = "ungrouped-heading"
+	InputCIStatusIndicators           string = "ci-status-indicators"
+	InputSizeBadges                   string = "size-badges"
+	InputSizeBadgeThresholds          string = "size-badge-thresholds"
 )
 
+const (
+	defaultGithubMaxPages        = 10
+	defaultReadyToMergeApprovals = 1
+	defaultUngroupedHeading      = "Ungrouped"
+)
+
+type FetchInputs struct {
+	// Maximum number of result pages to fetch per repository (PRs) and per PR (reviews)
+	MaxPages int
+	// If true, repositories that cannot be read are reported instead of failing the whole run
+	ContinueOnRepositoryErrors bool
+	// Defines how repository filters are combined with global filters
+	FiltersMergeStrategy FiltersMergeStrategy
+	// If true, the timeline events of PRs are fetched (required by some age bases)
+	FetchTimelineEvents bool
+	// If true, the details of PRs are refetched until their mergeability is known
+	FetchMergeability bool
+	// If true, the details of PRs are fetched for their size (also if FetchMergeability is set)
+	FetchSize bool
+	// If true, the commit statuses and check runs of the head commits of PRs are fetched
+	FetchCIStatus bool
+	// The age of PRs in filter expressions is measured as in the message (see ContentInputs)
+	AgeBasis              AgeBasis
+	BusinessHoursCalendar *workcalendar.Calendar
+}
+
+// Defines how reviewers (and requested reviewers) of PRs are shown in the message
+type ReviewerDisplay string
+
+const (
+	ReviewerDisplayMention ReviewerDisplay = "mention" // Slack mentions if Slack user IDs are available
+	ReviewerDisplayName    ReviewerDisplay = "name"    // plain names, no notifications (default)
+	ReviewerDisplayNone    ReviewerDisplay = "none"    // reviewers are not shown
+)
+
+// Defines how PRs are grouped into lists in the message
+type GroupBy string
+
+const (
+	GroupByNone       GroupBy = "none"       // PRs are listed by age (default)
+	GroupByRepository GroupBy = "repository" // one list per repository
+	GroupByLabel      GroupBy = "label"      // one list per label group
+	GroupByReviewer   GroupBy = "reviewer"   // one list per requested reviewer (user or team)
+	GroupByAuthor     GroupBy = "author"     // one list per author split by who needs to act
+)
+
+// Defines the order of PRs within each list of the message
+type SortBy string
+
+const (
+	SortByNewest     SortBy = "newest"     // newest first (default)
+	SortByOldest     SortBy = "oldest"     // oldest first
+	SortByUpdated    SortBy = "updated"    // most recently updated first
+	SortByApprovals  SortBy = "approvals"  // most approvals first
+	SortBySize       SortBy = "size"       // smallest first (PRs of unknown size last)
+	SortByRepository SortBy = "repository" // by repository path, newest first within a repository
+)
+
+// Defines from which point in time the age of PRs is measured
+type AgeBasis string
+
+const (
+	AgeBasisCreated         AgeBasis = "created"          // when the PR was opened (default)
+	AgeBasisReadyForReview  AgeBasis = "ready-for-review" // when the PR was last marked ready for review
+	AgeBasisUpdated         AgeBasis = "updated"          // when the PR was last updated
+	AgeBasisReviewRequested AgeBasis = "review-requested" // when a review was last requested
+)
+
+// Returns true if the timeline events of PRs are needed to measure their age.
+func (b AgeBasis) RequiresTimelineEvents() bool {
+	return b == AgeBasisReadyForReview || b == AgeBasisReviewRequested
+}
+
 type ContentInputs struct {
 	NoPRsMessage        string
 	MainListHeading     string
 	OldPRsListHeading   string
 	OldPRThresholdHours *int
+	// If set, PRs are listed in these categories by age (instead of the main and old PRs lists)
+	AgeCategories []AgeCategory
+	AgeBasis      AgeBasis
+	// If set, the age of PRs is measured in working hours of the calendar
+	BusinessHoursCalendar *workcalendar.Calendar
+	// If set, draft PRs are listed separately under this heading
+	DraftPRsListHeading string
+	// If set, PRs that are ready to merge are listed first under this heading
+	ReadyToMergeListHeading string
+	// The number of approvals required for a PR to be ready to merge
+	ReadyToMergeApprovals int
+	// If set, PRs with merge conflicts are listed separately under this heading
+	NeedsRebaseListHeading string
+	// If true, approvals of an older revision than the PR head are not counted as approvals
+	IgnoreStaleApprovals bool
+	ReviewerDisplay      ReviewerDisplay
+	// If set (other than GroupByNone), PRs are listed in groups instead of the main list
+	GroupBy GroupBy
+	SortBy  SortBy
+	// Label groups used if PRs are grouped by label, PRs that are not in any group (or that
+	// have no requested reviewers if grouped by reviewer) are listed under UngroupedHeading
+	LabelGroups      []LabelGroup
+	UngroupedHeading string
+	// If set, PRs are shown with size badges (XS, S, M, L or XL)
+	SizeBadgeThresholds *SizeThresholds
+	// If true, PRs are shown with an indicator of their CI status (e.g. ✅)
+	CIStatusIndicators bool
+}
+
+// Returns true if the mergeability of PRs is needed for the message, i.e. for listing PRs
+// that need a rebase, PRs that are ready to merge or PRs waiting on their authors.
+func (i ContentInputs) RequiresMergeability() bool {
+	return i.NeedsRebaseListHeading != "" || i.ReadyToMergeListHeading != "" || i.GroupBy == GroupByAuthor
+}
+
+// Returns true if the CI status of PRs is needed for the message, i.e. for the CI status
+// indicators, for PRs that are ready to merge or for PRs waiting on their authors.
+func (i ContentInputs) RequiresCIStatus() bool {
+	return i.CIStatusIndicators || i.ReadyToMergeListHeading != "" || i.GroupBy == GroupByAuthor
 }
 
 type Config struct {
@@ -39,9 +176,15 @@ type Config struct {
 	SlackChannelName            string
 	SlackChannelID              string
 	SlackUserIdByGitHubUsername map[string]string
-	ContentInputs               ContentInputs
-	GlobalFilters               Filters
-	RepositoryFilters           map[string]Filters
+	// Slack user group IDs by GitHub team slugs
+	SlackUserGroupIdByGitHubTeam map[string]string
+	ContentInputs                ContentInputs
+	FetchInputs                  FetchInputs
+	GlobalFilters                Filters
+	RepositoryFilters            map[string]Filters
+	WorkingCalendar              workcalendar.Calendar
+	// If true, the action exits without sending a message on non-working days of the calendar
+	SkipNonWorkingDays bool
 }
 
 func (c Config) Print() {
@@ -61,13 +204,55 @@ func GetConfig() (Config, error) {
 	repository, err1 := utilities.GetEnvRequired(EnvGithubRepository)
 	githubToken, err2 := utilities.GetInputRequired(InputGithubToken)
 	slackToken, err3 := utilities.GetInputRequired(InputSlackBotToken)
-	mainListHeading, err4 := utilities.GetInputRequired(InputMainListHeading)
+	mainListHeading := utilities.GetInput(InputMainListHeading)
 	oldPRsThresholdHours, err5 := utilities.GetInputInt(InputOldPRThresholdHours)
 	slackUserIdByGitHubUsername, err6 := utilities.GetInputMapping(InputSlackUserIdByGitHubUsername)
 	globalFilters, err7 := GetGlobalFiltersFromInput(InputGlobalFilters)
 	repositoryFilters, err8 := GetRepositoryFiltersFromInput(InputRepositoryFilters)
+	maxPages, err9 := utilities.GetInputInt(InputGithubMaxPages)
+	continueOnRepositoryErrors, err10 := utilities.GetInputBool(InputContinueOnRepositoryErrors)
+	ignoreStaleApprovals, err11 := utilities.GetInputBool(InputIgnoreStaleApprovals)
+	reviewerDisplay, err12 := utilities.GetInputOption(
+		InputReviewerDisplay,
+		[]ReviewerDisplay{ReviewerDisplayMention, ReviewerDisplayName, ReviewerDisplayNone},
+		ReviewerDisplayName,
+	)
+	filtersMergeStrategy, err13 := utilities.GetInputOption(
+		InputFiltersMergeStrategy,
+		[]FiltersMergeStrategy{FiltersOverride, FiltersMerge, FiltersIntersect},
+		FiltersOverride,
+	)
+	sizeBadges, err14 := utilities.GetInputBool(InputSizeBadges)
+	sizeBadgeThresholds, err15 := GetSizeThresholdsFromInput(InputSizeBadgeThresholds)
+	readyToMergeApprovals, err16 := utilities.GetInputInt(InputReadyToMergeApprovals)
+	ageCategories, err17 := GetAgeCategoriesFromInput(InputAgeCategories)
+	ageBasis, err18 := utilities.GetInputOption(
+		InputAgeBasis,
+		[]AgeBasis{AgeBasisCreated, AgeBasisReadyForReview, AgeBasisUpdated, AgeBasisReviewRequested},
+		AgeBasisCreated,
+	)
+	businessHoursAge, err19 := utilities.GetInputBool(InputBusinessHoursAge)
+	workingCalendar, err20 := GetWorkingCalendarFromInputs(InputWorkingCalendar, InputHolidaysICalPath)
+	skipNonWorkingDays, err21 := utilities.GetInputBool(InputSkipNonWorkingDays)
+	groupBy, err22 := utilities.GetInputOption(
+		InputGroupBy,
+		[]GroupBy{GroupByNone, GroupByRepository, GroupByLabel, GroupByReviewer, GroupByAuthor},
+		GroupByNone,
+	)
+	labelGroups, err23 := GetLabelGroupsFromInput(InputLabelGroups)
+	sortBy, err24 := utilities.GetInputOption(
+		InputSortBy,
+		[]SortBy{SortByNewest, SortByOldest, SortByUpdated, SortByApprovals, SortBySize, SortByRepository},
+		SortByNewest,
+	)
+	slackUserGroupIdByGitHubTeam, err25 := utilities.GetInputMapping(InputSlackUserGroupIdByGitHubTeam)
+	ciStatusIndicators, err26 := utilities.GetInputBool(InputCIStatusIndicators)
 
-	if err := selectNonNilError(err1, err2, err3, err4, err5, err6, err7, err8); err != nil {
+	if err := selectNonNilError(
+		err1, err2, err3, err5, err6, err7, err8, err9, err10, err11, err12, err13,
+		err14, err15, err16, err17, err18, err19, err20, err21, err22, err23, err24,
+		err25, err26,
+	); err != nil {
 		return Config{}, err
 	}
 
@@ -85,32 +270,105 @@ func GetConfig() (Config, error) {
 	}
 
 	config := Config{
-		repository:                  repository,
-		Repositories:                repositories,
-		GithubToken:                 githubToken,
-		SlackBotToken:               slackToken,
-		SlackChannelName:            utilities.GetInput(InputSlackChannelName),
-		SlackChannelID:              utilities.GetInput(InputSlackChannelID),
-		SlackUserIdByGitHubUsername: slackUserIdByGitHubUsername,
+		repository:                   repository,
+		Repositories:                 repositories,
+		GithubToken:                  githubToken,
+		SlackBotToken:                slackToken,
+		SlackChannelName:             utilities.GetInput(InputSlackChannelName),
+		SlackChannelID:               utilities.GetInput(InputSlackChannelID),
+		SlackUserIdByGitHubUsername:  slackUserIdByGitHubUsername,
+		SlackUserGroupIdByGitHubTeam: slackUserGroupIdByGitHubTeam,
 		ContentInputs: ContentInputs{
-			NoPRsMessage:        utilities.GetInput(InputNoPRsMessage),
-			MainListHeading:     mainListHeading,
-			OldPRsListHeading:   utilities.GetInput(InputOldPRsListHeading),
-			OldPRThresholdHours: oldPRsThresholdHours,
+			NoPRsMessage:            utilities.GetInput(InputNoPRsMessage),
+			MainListHeading:         mainListHeading,
+			OldPRsListHeading:       utilities.GetInput(InputOldPRsListHeading),
+			OldPRThresholdHours:     oldPRsThresholdHours,
+			AgeCategories:           ageCategories,
+			AgeBasis:                ageBasis,
+			DraftPRsListHeading:     utilities.GetInput(InputDraftPRsListHeading),
+			ReadyToMergeListHeading: utilities.GetInput(InputReadyToMergeListHeading),
+			ReadyToMergeApprovals:   defaultReadyToMergeApprovals,
+			NeedsRebaseListHeading:  utilities.GetInput(InputNeedsRebaseListHeading),
+			IgnoreStaleApprovals:    ignoreStaleApprovals,
+			ReviewerDisplay:         reviewerDisplay,
+			GroupBy:                 groupBy,
+			SortBy:                  sortBy,
+			LabelGroups:             labelGroups,
+			UngroupedHeading:        cmp.Or(utilities.GetInput(InputUngroupedHeading), defaultUngroupedHeading),
+			CIStatusIndicators:      ciStatusIndicators,
+		},
+		FetchInputs: FetchInputs{
+			MaxPages:                   defaultGithubMaxPages,
+			ContinueOnRepositoryErrors: continueOnRepositoryErrors,
+			FiltersMergeStrategy:       filtersMergeStrategy,
+			FetchTimelineEvents:        ageBasis.RequiresTimelineEvents(),
+			AgeBasis:                   ageBasis,
 		},
-		GlobalFilters:     globalFilters,
-		RepositoryFilters: repositoryFilters,
+		GlobalFilters:      globalFilters,
+		RepositoryFilters:  repositoryFilters,
+		WorkingCalendar:    workingCalendar,
+		SkipNonWorkingDays: skipNonWorkingDays,
+	}
+	config.FetchInputs.FetchMergeability = config.ContentInputs.RequiresMergeability()
+	config.FetchInputs.FetchSize = sizeBadges || sortBy == SortBySize ||
+		anyFilters(globalFilters, repositoryFilters, Filters.RequiresSize)
+	config.FetchInputs.FetchCIStatus = config.ContentInputs.RequiresCIStatus() ||
+		anyFilters(globalFilters, repositoryFilters, Filters.RequiresCIStatus)
+	if readyToMergeApprovals != nil {
+		if *readyToMergeApprovals < 1 {
+			return Config{}, fmt.Errorf("%s must be a positive integer", InputReadyToMergeApprovals)
+		}
+		config.ContentInputs.ReadyToMergeApprovals = *readyToMergeApprovals
+	}
+	if sizeBadges {
+		config.ContentInputs.SizeBadgeThresholds = &sizeBadgeThresholds
+	}
+	if businessHoursAge {
+		config.ContentInputs.BusinessHoursCalendar = &workingCalendar
+		config.FetchInputs.BusinessHoursCalendar = &workingCalendar
+	}
+	if maxPages != nil {
+		if *maxPages < 1 {
+			return Config{}, fmt.Errorf("%s must be a positive integer", InputGithubMaxPages)
+		}
+		config.FetchInputs.MaxPages = *maxPages
 	}
 	if config.SlackChannelID == "" && config.SlackChannelName == "" {
 		return Config{}, fmt.Errorf(
 			"either %s or %s must be set", InputSlackChannelID, InputSlackChannelName,
 		)
 	}
+	if mainListHeading == "" && len(config.ContentInputs.AgeCategories) == 0 && groupBy == GroupByNone {
+		return Config{}, fmt.Errorf(
+			"%s must be set unless %s or %s is used", InputMainListHeading, InputAgeCategories, InputGroupBy,
+		)
+	}
 	if config.ContentInputs.OldPRThresholdHours != nil && config.ContentInputs.OldPRsListHeading == "" {
 		return Config{}, fmt.Errorf(
 			"if %s is set, %s must also be set", InputOldPRThresholdHours, InputOldPRsListHeading,
 		)
 	}
+	if len(config.ContentInputs.AgeCategories) > 0 && config.ContentInputs.OldPRThresholdHours != nil {
+		return Config{}, fmt.Errorf(
+			"cannot use both %s and %s", InputAgeCategories, InputOldPRThresholdHours,
+		)
+	}
+	if filtersMergeStrategy == FiltersMerge {
+		if err := validateMergedFilters(globalFilters, repositoryFilters); err != nil {
+			return Config{}, err
+		}
+	}
+	if groupBy == GroupByLabel && len(labelGroups) == 0 {
+		return Config{}, fmt.Errorf("if %s is %s, %s must also be set", InputGroupBy, GroupByLabel, InputLabelGroups)
+	}
+	if groupBy != GroupByNone {
+		if len(config.ContentInputs.AgeCategories) > 0 {
+			return Config{}, fmt.Errorf("cannot use both %s and %s", InputGroupBy, InputAgeCategories)
+		}
+		if config.ContentInputs.OldPRThresholdHours != nil {
+			return Config{}, fmt.Errorf("cannot use both %s and %s", InputGroupBy, InputOldPRThresholdHours)
+		}
+	}
 	return config, nil
 }
 
@@ -0,0 +1,225 @@
+// Package expression implements a small boolean expression language for filtering PRs, e.g.
+//
+//	"urgent" in labels or (author in ["alice", "bob"] and not draft)
+//
+// Supported fields: author, repo, base_branch, head_branch (strings), labels (list of strings),
+// draft (boolean), age (hours, measured as configured for the message), size (lines changed),
+// files (number of changed files) and ci (CI status: "passing", "failing", "pending", "none"
+// or ""). Supported operators: and, or, not, ==, !=, <, <=, >, >= and in. Expressions are
+// type-checked when parsed, so evaluating a parsed expression cannot fail. Comparisons with
+// unknown values (e.g. the size of a PR whose details could not be fetched) are always false.
+package expression
+
+import (
+	"fmt"
+	"slices"
+)
+
+// The values of a PR that expressions are evaluated against
+type Fields struct {
+	Author     string
+	Repository string
+	BaseBranch string
+	HeadBranch string
+	Labels     []string
+	Draft      bool
+	AgeHours   float64
+	Size       *int   // lines changed (additions + deletions), nil if unknown
+	Files      *int   // number of changed files, nil if unknown
+	CIStatus   string // e.g. "passing"
+}
+
+type valueType string
+
+const (
+	typeString     valueType = "string"
+	typeStringList valueType = "list"
+	typeNumber     valueType = "number"
+	typeBool       valueType = "boolean"
+)
+
+var fieldTypes = map[string]valueType{
+	"author":      typeString,
+	"repo":        typeString,
+	"base_branch": typeString,
+	"head_branch": typeString,
+	"labels":      typeStringList,
+	"draft":       typeBool,
+	"age":         typeNumber,
+	"size":        typeNumber,
+	"files":       typeNumber,
+	"ci":          typeString,
+}
+
+func (f Fields) get(name string) any {
+	switch name {
+	case "author":
+		return f.Author
+	case "repo":
+		return f.Repository
+	case "base_branch":
+		return f.BaseBranch
+	case "head_branch":
+		return f.HeadBranch
+	case "labels":
+		return f.Labels
+	case "draft":
+		return f.Draft
+	case "age":
+		return f.AgeHours
+	case "size":
+		return getOptionalNumber(f.Size)
+	case "files":
+		return getOptionalNumber(f.Files)
+	case "ci":
+		return f.CIStatus
+	}
+	panic(fmt.Sprintf("unknown field %s", name)) // unknown fields are rejected when parsing
+}
+
+// Returns nil for unknown values (see comparisonNode.evaluate).
+func getOptionalNumber(value *int) any {
+	if value == nil {
+		return nil
+	}
+	return float64(*value)
+}
+
+type Expression struct {
+	source string
+	root   node
+	fields []string
+}
+
+// Parses and type-checks the expression.
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s", next)
+	}
+	if root.valueType() != typeBool {
+		return nil, fmt.Errorf("expression must evaluate to a boolean, got %s", root.valueType())
+	}
+	return &Expression{source: source, root: root, fields: p.fields}, nil
+}
+
+func (e *Expression) Evaluate(fields Fields) bool {
+	return e.root.evaluate(fields).(bool)
+}
+
+// Returns true if the expression refers to the field, e.g. "size".
+func (e *Expression) UsesField(name string) bool {
+	return slices.Contains(e.fields, name)
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+type node interface {
+	valueType() valueType
+	evaluate(fields Fields) any
+}
+
+type literalNode struct {
+	value any
+	typ   valueType
+}
+
+func (n literalNode) valueType() valueType       { return n.typ }
+func (n literalNode) evaluate(fields Fields) any { return n.value }
+
+type fieldNode struct {
+	name string
+}
+
+func (n fieldNode) valueType() valueType       { return fieldTypes[n.name] }
+func (n fieldNode) evaluate(fields Fields) any { return fields.get(n.name) }
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) valueType() valueType { return typeBool }
+func (n notNode) evaluate(fields Fields) any {
+	return !n.operand.evaluate(fields).(bool)
+}
+
+type logicalNode struct {
+	operator    string // "and" or "or"
+	left, right node
+}
+
+func (n logicalNode) valueType() valueType { return typeBool }
+func (n logicalNode) evaluate(fields Fields) any {
+	left := n.left.evaluate(fields).(bool)
+	if n.operator == "and" {
+		return left && n.right.evaluate(fields).(bool)
+	}
+	return left || n.right.evaluate(fields).(bool)
+}
+
+type comparisonNode struct {
+	operator    string
+	left, right node
+}
+
+func (n comparisonNode) valueType() valueType { return typeBool }
+func (n comparisonNode) evaluate(fields Fields) any {
+	left, right := n.left.evaluate(fields), n.right.evaluate(fields)
+	if left == nil || right == nil {
+		return false
+	}
+	switch n.operator {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	case "in":
+		return slices.Contains(right.([]string), left.(string))
+	case "<":
+		return left.(float64) < right.(float64)
+	case "<=":
+		return left.(float64) <= right.(float64)
+	case ">":
+		return left.(float64) > right.(float64)
+	case ">=":
+		return left.(float64) >= right.(float64)
+	}
+	panic(fmt.Sprintf("unknown operator %s", n.operator)) // unknown operators are rejected when parsing
+}
+
+func newComparisonNode(operator token, left node, right node) (node, error) {
+	leftType, rightType := left.valueType(), right.valueType()
+	switch operator.text {
+	case "==", "!=":
+		if leftType != rightType || leftType == typeStringList {
+			return nil, fmt.Errorf(
+				"cannot compare %s with %s using %s", leftType, rightType, operator,
+			)
+		}
+	case "<", "<=", ">", ">=":
+		if leftType != typeNumber || rightType != typeNumber {
+			return nil, fmt.Errorf(
+				"cannot compare %s with %s using %s (only numbers can be ordered)",
+				leftType, rightType, operator,
+			)
+		}
+	case "in":
+		if leftType != typeString || rightType != typeStringList {
+			return nil, fmt.Errorf(
+				"%s requires a string on the left and a list on the right, got %s and %s",
+				operator, leftType, rightType,
+			)
+		}
+	}
+	return comparisonNode{operator: operator.text, left: left, right: right}, nil
+}
@@ -0,0 +1,126 @@
+package expression_test
+
+import (
+	"testing"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/config/expression"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestEvaluate(t *testing.T) {
+	fields := expression.Fields{
+		Author:     "alice",
+		Repository: "frontend",
+		BaseBranch: "main",
+		HeadBranch: "feature/login",
+		Labels:     []string{"enhancement", "urgent"},
+		Draft:      false,
+		AgeHours:   30,
+		Size:       intPtr(120),
+		Files:      intPtr(4),
+	}
+	testCases := []struct {
+		source   string
+		expected bool
+	}{
+		{`"urgent" in labels`, true},
+		{`"wip" in labels`, false},
+		{`author in ["alice", "bob"] and not draft`, true},
+		{`"wip" in labels or (author in ["bob"] and not draft)`, false},
+		{`"wip" in labels or author == "alice" and draft`, false},
+		{`draft or age >= 24`, true},
+		{`size < 100`, false},
+		{`size > 100 and files <= 5`, true},
+		{`repo != 'backend' and base_branch == "main"`, true},
+		{`not (head_branch == "feature/login")`, false},
+		{`draft == false and true`, true},
+		{`author in []`, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			parsed, err := expression.Parse(tc.source)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if got := parsed.Evaluate(fields); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestEvaluateUnknownValues(t *testing.T) {
+	fields := expression.Fields{Author: "alice"} // size and files are unknown
+	testCases := []struct {
+		source   string
+		expected bool
+	}{
+		{`size < 100`, false},
+		{`size >= 100`, false},
+		{`files == 0`, false},
+		{`files != 0`, false},
+		{`size > 100 or author == "alice"`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			parsed, err := expression.Parse(tc.source)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if got := parsed.Evaluate(fields); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestUsesField(t *testing.T) {
+	parsed, err := expression.Parse(`size > 100 and not ("wip" in labels)`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for field, expected := range map[string]bool{"size": true, "labels": true, "files": false, "ci": false} {
+		if got := parsed.UsesField(field); got != expected {
+			t.Errorf("Expected UsesField(%q) to be %v, got %v", field, expected, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		source        string
+		expectedError string
+	}{
+		{`reviewer == "alice"`, `unknown field "reviewer" at position 1`},
+		{`author`, `expression must evaluate to a boolean, got string`},
+		{`author == 1`, `cannot compare string with number using "==" at position 8`},
+		{`labels == ["a"]`, `cannot compare list with list using "==" at position 8`},
+		{`author > "a"`, `cannot compare string with string using ">" at position 8 (only numbers can be ordered)`},
+		{`labels in ["a"]`, `"in" at position 8 requires a string on the left and a list on the right, got list and list`},
+		{`age and draft`, `"and" at position 5 requires boolean operands, got number`},
+		{`not author`, `"not" at position 1 requires a boolean operand, got string`},
+		{`(draft`, `expected ")", got end of expression`},
+		{`draft draft`, `unexpected "draft" at position 7`},
+		{`author in ["a" "b"]`, `expected "," or "]", got "b" at position 16`},
+		{`author == "alice`, `unterminated string starting at position 11`},
+		{`author = "alice"`, `unexpected character '=' at position 8`},
+		{`draft or`, `unexpected end of expression`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			_, err := expression.Parse(tc.source)
+			if err == nil {
+				t.Fatalf("Expected error '%s', got nil", tc.expectedError)
+			}
+			if err.Error() != tc.expectedError {
+				t.Errorf("Expected error '%s', got '%v'", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBracket
+	tokenRightBracket
+	tokenComma
+)
+
+type token struct {
+	kind     tokenKind
+	text     string // identifier, operator or literal value (without quotes for strings)
+	position int    // byte offset in the source (for error messages)
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", t.text, t.position+1)
+}
+
+var twoCharOperators = []string{"==", "!=", "<=", ">="}
+
+func tokenize(source string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", position: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", position: i})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{kind: tokenLeftBracket, text: "[", position: i})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{kind: tokenRightBracket, text: "]", position: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", position: i})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string starting at position %d", i+1)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : end]), position: i})
+			i = end + 1
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:end]), position: i})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: string(runes[i:end]), position: i})
+			i = end
+		default:
+			operator := ""
+			if i+1 < len(runes) {
+				for _, candidate := range twoCharOperators {
+					if string(runes[i:i+2]) == candidate {
+						operator = candidate
+					}
+				}
+			}
+			if operator == "" && strings.ContainsRune("<>", r) {
+				operator = string(r)
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i+1)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, position: i})
+			i += len([]rune(operator))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, position: len(runes)}), nil
+}
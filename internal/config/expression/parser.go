@@ -0,0 +1,169 @@
+package expression
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// A recursive descent parser for the grammar:
+//
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | comparison
+//	comparison = operand [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" | "in" ) operand ]
+//	operand    = "(" or ")" | field | string | number | "true" | "false" | list
+//	list       = "[" [ string { "," string } ] "]"
+type parser struct {
+	tokens   []token
+	position int
+	fields   []string // the names of the fields used in the expression
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != tokenEOF {
+		p.position++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenIdentifier && t.text == keyword
+}
+
+func (p *parser) expect(kind tokenKind, description string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, fmt.Errorf("expected %s, got %s", description, t)
+	}
+	return t, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseLogical("or", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseLogical("and", p.parseUnary)
+}
+
+func (p *parser) parseLogical(operator string, parseOperand func() (node, error)) (node, error) {
+	left, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword(operator) {
+		operatorToken := p.next()
+		right, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		for _, operand := range []node{left, right} {
+			if operand.valueType() != typeBool {
+				return nil, fmt.Errorf("%s requires boolean operands, got %s", operatorToken, operand.valueType())
+			}
+		}
+		left = logicalNode{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if !p.isKeyword("not") {
+		return p.parseComparison()
+	}
+	operatorToken := p.next()
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if operand.valueType() != typeBool {
+		return nil, fmt.Errorf("%s requires a boolean operand, got %s", operatorToken, operand.valueType())
+	}
+	return notNode{operand: operand}, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenOperator && !p.isKeyword("in") {
+		return left, nil
+	}
+	operatorToken := p.next()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return newComparisonNode(operatorToken, left, right)
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLeftParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRightParen, `")"`); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokenLeftBracket:
+		return p.parseList()
+	case tokenString:
+		return literalNode{value: t.text, typ: typeString}, nil
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", t)
+		}
+		return literalNode{value: value, typ: typeNumber}, nil
+	case tokenIdentifier:
+		switch t.text {
+		case "true", "false":
+			return literalNode{value: t.text == "true", typ: typeBool}, nil
+		case "and", "or", "not", "in":
+			return nil, fmt.Errorf("unexpected %s", t)
+		}
+		if _, ok := fieldTypes[t.text]; !ok {
+			return nil, fmt.Errorf("unknown field %s", t)
+		}
+		if !slices.Contains(p.fields, t.text) {
+			p.fields = append(p.fields, t.text)
+		}
+		return fieldNode{name: t.text}, nil
+	}
+	return nil, fmt.Errorf("unexpected %s", t)
+}
+
+func (p *parser) parseList() (node, error) {
+	values := []string{}
+	if p.peek().kind == tokenRightBracket {
+		p.next()
+		return literalNode{value: values, typ: typeStringList}, nil
+	}
+	for {
+		value, err := p.expect(tokenString, "a string")
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value.text)
+		separator := p.next()
+		switch separator.kind {
+		case tokenComma:
+			continue
+		case tokenRightBracket:
+			return literalNode{value: values, typ: typeStringList}, nil
+		}
+		return nil, fmt.Errorf(`expected "," or "]", got %s`, separator)
+	}
+}
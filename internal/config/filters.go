@@ -4,16 +4,149 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"slices"
 
+	"github.com/hellej/pr-slack-reminder-action/internal/config/expression"
 	"github.com/hellej/pr-slack-reminder-action/internal/config/utilities"
 )
 
+// Defines how draft PRs are filtered
+type DraftsFilter string
+
+const (
+	DraftsInclude DraftsFilter = "include" // default
+	DraftsExclude DraftsFilter = "exclude"
+	DraftsOnly    DraftsFilter = "only"
+)
+
+// Defines which PRs are included by the CI status of their head commit
+type CIFilter string
+
+const (
+	CIPassing CIFilter = "passing"
+	CIFailing CIFilter = "failing"
+	CIPending CIFilter = "pending"
+)
+
+// Defines how repository specific filters are combined with the global filters
+type FiltersMergeStrategy string
+
+const (
+	// Repository filters replace the global filters (default)
+	FiltersOverride FiltersMergeStrategy = "override"
+	// Filters set in repository filters replace the corresponding global filters,
+	// other global filters still apply
+	FiltersMerge FiltersMergeStrategy = "merge"
+	// PRs must match both the repository filters and the global filters
+	FiltersIntersect FiltersMergeStrategy = "intersect"
+)
+
+// All string list filters are patterns (see Pattern) that support globs (e.g. "area/*" or
+// "*[bot]") and regular expressions prefixed with "re:" (e.g. "re:^team:payments-.+$").
 type Filters struct {
-	Authors       []string `json:"authors,omitempty"`
-	AuthorsIgnore []string `json:"authors-ignore,omitempty"`
-	Labels        []string `json:"labels,omitempty"`
-	LabelsIgnore  []string `json:"labels-ignore,omitempty"`
+	Authors            Patterns     `json:"authors,omitempty"`
+	AuthorsIgnore      Patterns     `json:"authors-ignore,omitempty"`
+	Labels             Patterns     `json:"labels,omitempty"`
+	LabelsIgnore       Patterns     `json:"labels-ignore,omitempty"`
+	Drafts             DraftsFilter `json:"drafts,omitempty"`
+	BaseBranches       Patterns     `json:"base-branches,omitempty"`
+	BaseBranchesIgnore Patterns     `json:"base-branches-ignore,omitempty"`
+	HeadBranches       Patterns     `json:"head-branches,omitempty"`
+	HeadBranchesIgnore Patterns     `json:"head-branches-ignore,omitempty"`
+	// Limits for the number of lines changed (additions + deletions) in PRs, PRs whose size
+	// is unknown are excluded if either is set
+	MinSize *int `json:"min-size,omitempty"`
+	MaxSize *int `json:"max-size,omitempty"`
+	// PRs whose CI status is unknown (or that have no CI) are excluded if set
+	CI CIFilter `json:"ci,omitempty"`
+	// An expression that PRs must also match, e.g. `"urgent" in labels or not draft`
+	// (see package expression for the syntax)
+	Expression string `json:"expression,omitempty"`
+	// The parsed Expression (set by parseFilters, nil if there is no expression)
+	expression *expression.Expression
+}
+
+// Returns true if there is no expression filter or if the fields match it.
+func (f Filters) MatchesExpression(fields expression.Fields) bool {
+	return f.expression == nil || f.expression.Evaluate(fields)
+}
+
+// Returns true if the size of PRs is needed for filtering them (see FetchInputs.FetchSize).
+func (f Filters) RequiresSize() bool {
+	return f.MinSize != nil || f.MaxSize != nil ||
+		f.expression != nil && (f.expression.UsesField("size") || f.expression.UsesField("files"))
+}
+
+// Returns true if the CI status of PRs is needed for filtering them.
+func (f Filters) RequiresCIStatus() bool {
+	return f.CI != "" || f.expression != nil && f.expression.UsesField("ci")
+}
+
+// Returns true if the predicate is true for the global filters or any repository filters.
+func anyFilters(
+	globalFilters Filters, repositoryFilters map[string]Filters, predicate func(Filters) bool,
+) bool {
+	if predicate(globalFilters) {
+		return true
+	}
+	for _, filters := range repositoryFilters {
+		if predicate(filters) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the filters with the filters that are set in the overrides replacing the
+// corresponding filters.
+func (f Filters) MergedWith(overrides Filters) Filters {
+	merged := f
+	for _, field := range []struct {
+		target   *Patterns
+		override Patterns
+	}{
+		{&merged.Authors, overrides.Authors},
+		{&merged.AuthorsIgnore, overrides.AuthorsIgnore},
+		{&merged.Labels, overrides.Labels},
+		{&merged.LabelsIgnore, overrides.LabelsIgnore},
+		{&merged.BaseBranches, overrides.BaseBranches},
+		{&merged.BaseBranchesIgnore, overrides.BaseBranchesIgnore},
+		{&merged.HeadBranches, overrides.HeadBranches},
+		{&merged.HeadBranchesIgnore, overrides.HeadBranchesIgnore},
+	} {
+		if len(field.override) > 0 {
+			*field.target = field.override
+		}
+	}
+	if overrides.Drafts != "" {
+		merged.Drafts = overrides.Drafts
+	}
+	if overrides.CI != "" {
+		merged.CI = overrides.CI
+	}
+	if overrides.MinSize != nil {
+		merged.MinSize = overrides.MinSize
+	}
+	if overrides.MaxSize != nil {
+		merged.MaxSize = overrides.MaxSize
+	}
+	if overrides.Expression != "" {
+		merged.Expression = overrides.Expression
+		merged.expression = overrides.expression
+	}
+	return merged
+}
+
+// Returns an error if the filters of any repository conflict with the global filters when
+// merged with them (e.g. authors set globally and authors-ignore for a repository).
+func validateMergedFilters(globalFilters Filters, repositoryFilters map[string]Filters) error {
+	for _, repo := range slices.Sorted(maps.Keys(repositoryFilters)) {
+		if err := globalFilters.MergedWith(repositoryFilters[repo]).validate(); err != nil {
+			return fmt.Errorf("invalid filters for repository %s when merged with global filters: %v", repo, err)
+		}
+	}
+	return nil
 }
 
 func (f Filters) validate() error {
@@ -21,11 +154,34 @@ func (f Filters) validate() error {
 		return fmt.Errorf("cannot use both authors and authors-ignore filters at the same time")
 	}
 
-	if slices.ContainsFunc(f.Labels, func(author string) bool {
-		return slices.Contains(f.LabelsIgnore, author)
-	}) {
+	if f.Labels.overlapsWith(f.LabelsIgnore) {
 		return fmt.Errorf("labels filter cannot contain labels that are in labels-ignore filter")
 	}
+
+	if f.BaseBranches.overlapsWith(f.BaseBranchesIgnore) {
+		return fmt.Errorf("base-branches filter cannot contain patterns that are in base-branches-ignore filter")
+	}
+
+	if f.HeadBranches.overlapsWith(f.HeadBranchesIgnore) {
+		return fmt.Errorf("head-branches filter cannot contain patterns that are in head-branches-ignore filter")
+	}
+
+	if f.Drafts != "" && !slices.Contains(
+		[]DraftsFilter{DraftsInclude, DraftsExclude, DraftsOnly}, f.Drafts,
+	) {
+		return fmt.Errorf("drafts filter must be one of %s, %s or %s", DraftsInclude, DraftsExclude, DraftsOnly)
+	}
+
+	if f.CI != "" && !slices.Contains([]CIFilter{CIPassing, CIFailing, CIPending}, f.CI) {
+		return fmt.Errorf("ci filter must be one of %s, %s or %s", CIPassing, CIFailing, CIPending)
+	}
+
+	if (f.MinSize != nil && *f.MinSize < 0) || (f.MaxSize != nil && *f.MaxSize < 0) {
+		return fmt.Errorf("min-size and max-size filters cannot be negative")
+	}
+	if f.MinSize != nil && f.MaxSize != nil && *f.MinSize > *f.MaxSize {
+		return fmt.Errorf("min-size filter cannot be greater than max-size filter")
+	}
 	return nil
 }
 
@@ -45,6 +201,14 @@ func parseFilters(rawFilters string) (Filters, error) {
 	if err != nil {
 		return Filters{}, fmt.Errorf("invalid filters: %v, error: %v", rawFilters, err)
 	}
+	if filters.Expression != "" {
+		filters.expression, err = expression.Parse(filters.Expression)
+		if err != nil {
+			return Filters{}, fmt.Errorf(
+				"invalid filters: %v, error: invalid expression: %v", rawFilters, err,
+			)
+		}
+	}
 
 	return filters, nil
 }
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/config/utilities"
+)
+
+// PRs with any label matching the label patterns of the group are listed under the name
+// of the group (e.g. the team owning the PRs).
+type LabelGroup struct {
+	Name   string
+	Labels Patterns
+}
+
+// Reads the label groups from a list input where each line is in format
+// "<group name>: <label pattern>, <label pattern>...", e.g. "Payments: team:payments" and
+// "Search: team:search, area/search*". The groups are returned in the order of the input.
+func GetLabelGroupsFromInput(input string) ([]LabelGroup, error) {
+	groups := []LabelGroup{}
+	for _, line := range utilities.GetInputList(input) {
+		if line == "" {
+			continue
+		}
+		name, rawLabels, found := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		rawPatterns := []string{}
+		for _, label := range strings.Split(rawLabels, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				rawPatterns = append(rawPatterns, label)
+			}
+		}
+		if !found || name == "" || len(rawPatterns) == 0 {
+			return nil, fmt.Errorf(
+				"invalid label group in input %s: %s (expected format \"<group name>: <label pattern>, ...\")",
+				input, line,
+			)
+		}
+		if slices.ContainsFunc(groups, func(g LabelGroup) bool { return g.Name == name }) {
+			return nil, fmt.Errorf("duplicate label group %s in input %s", name, input)
+		}
+		labels, err := NewPatterns(rawPatterns...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label group %s in input %s: %v", name, input, err)
+		}
+		groups = append(groups, LabelGroup{Name: name, Labels: labels})
+	}
+	return groups, nil
+}
+
+// Returns true if any of the labels matches the label patterns of the group.
+func (g LabelGroup) MatchesAnyLabel(labels []string) bool {
+	return slices.ContainsFunc(labels, func(label string) bool {
+		return g.Labels.MatchesAny(label)
+	})
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const regexPatternPrefix = "re:"
+
+// A glob or regular expression pattern that is compiled once when it is parsed. In glob
+// patterns, "*" matches any sequence of characters (including "/") and "?" matches any single
+// character, other characters are matched literally. Patterns prefixed with "re:" are used
+// as (unanchored) regular expressions as is, e.g. "re:^team:(payments|search)$". Empty
+// patterns (which would match every value) are invalid.
+type Pattern struct {
+	raw    string
+	regexp *regexp.Regexp
+}
+
+func NewPattern(pattern string) (Pattern, error) {
+	expression, isRegex := strings.CutPrefix(pattern, regexPatternPrefix)
+	if expression == "" {
+		return Pattern{}, fmt.Errorf("invalid pattern %q: pattern cannot be empty", pattern)
+	}
+	if !isRegex {
+		expression = regexp.QuoteMeta(expression)
+		expression = strings.ReplaceAll(expression, `\*`, ".*")
+		expression = strings.ReplaceAll(expression, `\?`, ".")
+		expression = "^" + expression + "$"
+	}
+	compiled, err := regexp.Compile(expression)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+	return Pattern{raw: pattern, regexp: compiled}, nil
+}
+
+// Returns the patterns compiled, or an error if any of them is invalid.
+func NewPatterns(patterns ...string) (Patterns, error) {
+	compiled := make(Patterns, len(patterns))
+	for i, pattern := range patterns {
+		var err error
+		if compiled[i], err = NewPattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+	return compiled, nil
+}
+
+func (p Pattern) String() string {
+	return p.raw
+}
+
+func (p Pattern) MatchString(value string) bool {
+	return p.regexp != nil && p.regexp.MatchString(value)
+}
+
+func (p *Pattern) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	pattern, err := NewPattern(raw)
+	if err != nil {
+		return err
+	}
+	*p = pattern
+	return nil
+}
+
+func (p Pattern) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.raw)
+}
+
+type Patterns []Pattern
+
+// Returns true if the value matches any of the patterns.
+func (p Patterns) MatchesAny(value string) bool {
+	return slices.ContainsFunc(p, func(pattern Pattern) bool {
+		return pattern.MatchString(value)
+	})
+}
+
+// Returns true if any of the patterns is also in the other patterns (as written).
+func (p Patterns) overlapsWith(other Patterns) bool {
+	return slices.ContainsFunc(p, func(pattern Pattern) bool {
+		return slices.ContainsFunc(other, func(o Pattern) bool { return o.raw == pattern.raw })
+	})
+}
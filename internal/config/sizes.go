@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/config/utilities"
+)
+
+// The size labels in ascending order (PRs larger than the L threshold are XL)
+var sizeLabels = []string{"XS", "S", "M", "L"}
+
+const sizeLabelXL = "XL"
+
+// Upper limits (inclusive) of lines changed (additions + deletions) for each size label
+type SizeThresholds struct {
+	XS int
+	S  int
+	M  int
+	L  int
+}
+
+var defaultSizeThresholds = SizeThresholds{XS: 10, S: 100, M: 500, L: 1000}
+
+// Returns the size label (XS, S, M, L or XL) for the number of lines changed.
+func (t SizeThresholds) GetSizeLabel(linesChanged int) string {
+	for i, limit := range t.limits() {
+		if linesChanged <= limit {
+			return sizeLabels[i]
+		}
+	}
+	return sizeLabelXL
+}
+
+func (t SizeThresholds) limits() []int {
+	return []int{t.XS, t.S, t.M, t.L}
+}
+
+// Reads the size thresholds from a mapping input like "XS: 10; S: 100; M: 500; L: 1000".
+// Thresholds that are not set in the input default to the default thresholds.
+func GetSizeThresholdsFromInput(input string) (SizeThresholds, error) {
+	mapping, err := utilities.GetInputMapping(input)
+	if err != nil {
+		return SizeThresholds{}, fmt.Errorf("error reading input %s: %w", input, err)
+	}
+	thresholds := defaultSizeThresholds
+	targets := map[string]*int{
+		"XS": &thresholds.XS, "S": &thresholds.S, "M": &thresholds.M, "L": &thresholds.L,
+	}
+	for label, value := range mapping {
+		target, ok := targets[label]
+		if !ok {
+			return SizeThresholds{}, fmt.Errorf(
+				"invalid size label %s in input %s (must be one of %v)", label, input, sizeLabels,
+			)
+		}
+		*target, err = strconv.Atoi(value)
+		if err != nil || *target < 0 {
+			return SizeThresholds{}, fmt.Errorf(
+				"invalid threshold for size %s in input %s: %s (must be a non-negative integer)",
+				label, input, value,
+			)
+		}
+	}
+	limits := thresholds.limits()
+	for i := 1; i < len(limits); i++ {
+		if limits[i] <= limits[i-1] {
+			return SizeThresholds{}, fmt.Errorf(
+				"size thresholds in input %s must be in ascending order (got %s: %d, %s: %d)",
+				input, sizeLabels[i-1], limits[i-1], sizeLabels[i], limits[i],
+			)
+		}
+	}
+	return thresholds, nil
+}
@@ -3,6 +3,7 @@ package utilities
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -51,6 +52,33 @@ func GetInputInt(name string) (*int, error) {
 	return &parsed, nil
 }
 
+// Retrieves the value of the input and parses it as a boolean.
+// Returns false if the environment variable is not set.
+func GetInputBool(name string) (bool, error) {
+	val := GetInput(name)
+	if val == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("error parsing input %s as boolean: %v", name, err)
+	}
+	return parsed, nil
+}
+
+// Retrieves the value of the input and checks that it is one of the allowed options.
+// Returns defaultValue if the environment variable is not set.
+func GetInputOption[T ~string](name string, options []T, defaultValue T) (T, error) {
+	val := GetInput(name)
+	if val == "" {
+		return defaultValue, nil
+	}
+	if !slices.Contains(options, T(val)) {
+		return defaultValue, fmt.Errorf("invalid value for input %s: %s (must be one of %v)", name, val, options)
+	}
+	return T(val), nil
+}
+
 func GetInputList(name string) []string {
 	val := GetInput(name)
 	if val == "" {
@@ -47,6 +47,49 @@ func TestReadInputIntInvalid(t *testing.T) {
 	}
 }
 
+func TestReadInputBool(t *testing.T) {
+	t.Setenv("INPUT_TEST", "true")
+	value, err := utilities.GetInputBool("test")
+	if err != nil || !value {
+		t.Errorf("Expected 'true', got '%v' (error: %v)", value, err)
+	}
+
+	notSetValue, err := utilities.GetInputBool("notSet")
+	if err != nil || notSetValue {
+		t.Errorf("Expected 'false', got '%v' (error: %v)", notSetValue, err)
+	}
+}
+
+func TestReadInputBoolInvalid(t *testing.T) {
+	t.Setenv("INPUT_TEST", "yes")
+	_, err := utilities.GetInputBool("test")
+	expectedError := "error parsing input test as boolean: strconv.ParseBool: parsing \"yes\": invalid syntax"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Expected error %v, got '%v'", expectedError, err)
+	}
+}
+
+func TestReadInputOption(t *testing.T) {
+	options := []string{"a", "b"}
+	value, err := utilities.GetInputOption("test", options, "a")
+	if err != nil || value != "a" {
+		t.Errorf("Expected default value 'a', got '%v' (error: %v)", value, err)
+	}
+
+	t.Setenv("INPUT_TEST", "b")
+	value, err = utilities.GetInputOption("test", options, "a")
+	if err != nil || value != "b" {
+		t.Errorf("Expected 'b', got '%v' (error: %v)", value, err)
+	}
+
+	t.Setenv("INPUT_TEST", "c")
+	_, err = utilities.GetInputOption("test", options, "a")
+	expectedError := "invalid value for input test: c (must be one of [a b])"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Expected error %v, got '%v'", expectedError, err)
+	}
+}
+
 func TestReadStringMapping(t *testing.T) {
 	t.Setenv("INPUT_TEST", "a:b;c:d")
 	mapping, _ := utilities.GetInputMapping("test")
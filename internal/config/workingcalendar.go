@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/config/utilities"
+	"github.com/hellej/pr-slack-reminder-action/internal/workcalendar"
+)
+
+var weekdaysByName = map[string]time.Weekday{
+	"mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday, "thu": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday, "sun": time.Sunday,
+}
+
+// The working calendar as configured in the input (all fields are optional)
+type workingCalendarInput struct {
+	Timezone     string   `json:"timezone,omitempty"`      // e.g. "Europe/Helsinki"
+	WorkingDays  []string `json:"working-days,omitempty"`  // e.g. ["mon", "tue", "wed", "thu", "fri"]
+	WorkingHours string   `json:"working-hours,omitempty"` // e.g. "09:00-17:00"
+	Holidays     []string `json:"holidays,omitempty"`      // ISO dates, e.g. ["2025-12-24"]
+}
+
+// Reads the working calendar from a JSON input like {"timezone": "Europe/Helsinki",
+// "working-days": ["mon", "tue", "wed", "thu", "fri"], "working-hours": "09:00-17:00",
+// "holidays": ["2025-12-24", "2025-12-25"]}. Fields that are not set in the input default
+// to the fields of workcalendar.NewDefaultCalendar. If the iCal path input is set, the dates
+// of the events in the iCal file are added to the holidays.
+func GetWorkingCalendarFromInputs(input string, iCalPathInput string) (workcalendar.Calendar, error) {
+	calendar, err := parseWorkingCalendar(input)
+	if err != nil {
+		return workcalendar.Calendar{}, err
+	}
+	iCalPath := utilities.GetInput(iCalPathInput)
+	if iCalPath == "" {
+		return calendar, nil
+	}
+	file, err := os.Open(iCalPath)
+	if err != nil {
+		return workcalendar.Calendar{}, fmt.Errorf("error reading input %s: %v", iCalPathInput, err)
+	}
+	defer file.Close()
+	holidays, err := workcalendar.ReadICalHolidays(file)
+	if err != nil {
+		return workcalendar.Calendar{}, fmt.Errorf("error reading input %s: %v", iCalPathInput, err)
+	}
+	calendar.Holidays = append(calendar.Holidays, holidays...)
+	return calendar, nil
+}
+
+func parseWorkingCalendar(input string) (workcalendar.Calendar, error) {
+	calendar := workcalendar.NewDefaultCalendar()
+	rawCalendar := utilities.GetInput(input)
+	if rawCalendar == "" {
+		return calendar, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(rawCalendar)))
+	dec.DisallowUnknownFields()
+	var calendarInput workingCalendarInput
+	if err := dec.Decode(&calendarInput); err != nil {
+		return workcalendar.Calendar{}, fmt.Errorf("error reading input %s: %v", input, err)
+	}
+	if err := calendarInput.applyTo(&calendar); err != nil {
+		return workcalendar.Calendar{}, fmt.Errorf("invalid input %s: %v", input, err)
+	}
+	return calendar, nil
+}
+
+func (i workingCalendarInput) applyTo(calendar *workcalendar.Calendar) error {
+	if i.Timezone != "" {
+		location, err := time.LoadLocation(i.Timezone)
+		if err != nil {
+			return fmt.Errorf("unknown timezone %s", i.Timezone)
+		}
+		calendar.Location = location
+	}
+	if i.WorkingDays != nil {
+		calendar.WorkingDays = []time.Weekday{}
+		for _, name := range i.WorkingDays {
+			weekday, ok := weekdaysByName[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf(
+					"invalid working day %s (must be one of mon, tue, wed, thu, fri, sat, sun)", name,
+				)
+			}
+			calendar.WorkingDays = append(calendar.WorkingDays, weekday)
+		}
+	}
+	if i.WorkingHours != "" {
+		start, end, err := parseWorkingHours(i.WorkingHours)
+		if err != nil {
+			return err
+		}
+		calendar.WorkdayStart = start
+		calendar.WorkdayEnd = end
+	}
+	for _, holiday := range i.Holidays {
+		if _, err := time.Parse(workcalendar.DateLayout, holiday); err != nil {
+			return fmt.Errorf("invalid holiday %s (expected format YYYY-MM-DD)", holiday)
+		}
+		calendar.Holidays = append(calendar.Holidays, holiday)
+	}
+	return nil
+}
+
+// Parses working hours like "09:00-17:00" as offsets from midnight.
+func parseWorkingHours(workingHours string) (time.Duration, time.Duration, error) {
+	invalidErr := fmt.Errorf(
+		"invalid working hours %s (expected format HH:MM-HH:MM with start before end)", workingHours,
+	)
+	rawStart, rawEnd, found := strings.Cut(workingHours, "-")
+	if !found {
+		return 0, 0, invalidErr
+	}
+	start, err1 := parseTimeOfDay(rawStart)
+	end, err2 := parseTimeOfDay(rawEnd)
+	if err1 != nil || err2 != nil || start >= end {
+		return 0, 0, invalidErr
+	}
+	return start, end, nil
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
@@ -1,6 +1,13 @@
 package messagebuilder
 
 import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
 	"github.com/hellej/pr-slack-reminder-action/internal/messagecontent"
 	"github.com/hellej/pr-slack-reminder-action/internal/prparser"
 	"github.com/slack-go/slack"
@@ -17,84 +24,176 @@ func getUserNameElement(pr prparser.PR) slack.RichTextSectionElement {
 	)
 }
 
-func getReviewersElements(pr prparser.PR) []slack.RichTextSectionElement {
+func getCollaboratorElement(
+	collaborator prparser.Collaborator, reviewerDisplay config.ReviewerDisplay,
+) slack.RichTextSectionElement {
+	if collaborator.SlackUserID != "" && reviewerDisplay == config.ReviewerDisplayMention {
+		return slack.NewRichTextSectionUserElement(
+			collaborator.SlackUserID, &slack.RichTextSectionTextStyle{},
+		)
+	}
+	return slack.NewRichTextSectionTextElement(
+		collaborator.GetGitHubName(), &slack.RichTextSectionTextStyle{},
+	)
+}
+
+type reviewerGroup struct {
+	prefix    string
+	suffix    string
+	reviewers []prparser.Collaborator
+}
+
+func getReviewersElements(
+	pr prparser.PR, reviewerDisplay config.ReviewerDisplay,
+) []slack.RichTextSectionElement {
 	var elements []slack.RichTextSectionElement
-	approverCount := len(pr.Approvers)
-	commenterCount := len(pr.Commenters)
+	if reviewerDisplay == config.ReviewerDisplayNone {
+		return elements
+	}
 
-	if approverCount == 0 && commenterCount == 0 {
+	if !pr.HasReviewInfo() {
 		return append(
 			elements, slack.NewRichTextSectionTextElement(
-				" (no reviews)", &slack.RichTextSectionTextStyle{},
+				" (reviews unknown)", &slack.RichTextSectionTextStyle{},
 			),
 		)
 	}
 
-	reviewerTextPrefix := " (reviewed by "
-	if len(pr.Approvers) > 0 {
-		reviewerTextPrefix = " (approved by "
+	reviewerGroups := []reviewerGroup{
+		{prefix: "approved by ", reviewers: pr.Approvers},
+		{suffix: " approved an older revision", reviewers: pr.StaleApprovers},
+		{prefix: "changes requested by ", reviewers: pr.ChangesRequesters},
+		{prefix: "reviewed by ", reviewers: pr.Commenters},
 	}
+	reviewerGroups = slices.DeleteFunc(reviewerGroups, func(g reviewerGroup) bool {
+		return len(g.reviewers) == 0
+	})
+
+	if len(reviewerGroups) == 0 {
+		return append(
+			elements, slack.NewRichTextSectionTextElement(
+				" (no reviews)", &slack.RichTextSectionTextStyle{},
+			),
+		)
+	}
+
 	elements = append(elements, slack.NewRichTextSectionTextElement(
-		reviewerTextPrefix, &slack.RichTextSectionTextStyle{},
+		" (", &slack.RichTextSectionTextStyle{},
 	))
-
-	for idx, approver := range pr.Approvers {
-		elements = append(elements, slack.NewRichTextSectionTextElement(
-			approver.GetGitHubName(), &slack.RichTextSectionTextStyle{},
-		))
-		if idx > 0 && idx < approverCount-1 {
+	for groupIdx, group := range reviewerGroups {
+		if groupIdx > 0 {
 			elements = append(elements, slack.NewRichTextSectionTextElement(
-				", ", &slack.RichTextSectionTextStyle{},
+				" - ", &slack.RichTextSectionTextStyle{},
+			))
+		}
+		if group.prefix != "" {
+			elements = append(elements, slack.NewRichTextSectionTextElement(
+				group.prefix, &slack.RichTextSectionTextStyle{},
+			))
+		}
+		for idx, reviewer := range group.reviewers {
+			if idx > 0 {
+				elements = append(elements, slack.NewRichTextSectionTextElement(
+					", ", &slack.RichTextSectionTextStyle{},
+				))
+			}
+			elements = append(elements, getCollaboratorElement(reviewer, reviewerDisplay))
+		}
+		if group.suffix != "" {
+			elements = append(elements, slack.NewRichTextSectionTextElement(
+				group.suffix, &slack.RichTextSectionTextStyle{},
 			))
 		}
 	}
 
-	if commenterCount == 0 {
-		return append(elements, slack.NewRichTextSectionTextElement(
-			")", &slack.RichTextSectionTextStyle{},
-		))
-	}
+	return append(elements, slack.NewRichTextSectionTextElement(
+		")", &slack.RichTextSectionTextStyle{},
+	))
+}
 
-	if reviewerTextPrefix == " (approved by " {
-		elements = append(elements, slack.NewRichTextSectionTextElement(
-			"- reviewed by ", &slack.RichTextSectionTextStyle{},
-		))
+func getRequestedReviewersElements(
+	pr prparser.PR, reviewerDisplay config.ReviewerDisplay,
+) []slack.RichTextSectionElement {
+	if len(pr.RequestedReviewers) == 0 && len(pr.RequestedTeams) == 0 ||
+		reviewerDisplay == config.ReviewerDisplayNone {
+		return nil
+	}
+	mention := reviewerDisplay == config.ReviewerDisplayMention
+	var mentions []slack.RichTextSectionElement
+	for _, reviewer := range pr.RequestedReviewers {
+		if reviewer.SlackUserID != "" && mention {
+			mentions = append(mentions, slack.NewRichTextSectionUserElement(
+				reviewer.SlackUserID, &slack.RichTextSectionTextStyle{},
+			))
+		} else {
+			mentions = append(mentions, slack.NewRichTextSectionTextElement(
+				reviewer.GetGitHubName(), &slack.RichTextSectionTextStyle{},
+			))
+		}
+	}
+	for _, team := range pr.RequestedTeams {
+		if team.SlackUserGroupID != "" && mention {
+			mentions = append(mentions, slack.NewRichTextSectionUserGroupElement(team.SlackUserGroupID))
+		} else {
+			mentions = append(mentions, slack.NewRichTextSectionTextElement(
+				cmp.Or(team.Name, team.Slug), &slack.RichTextSectionTextStyle{},
+			))
+		}
 	}
 
-	for idx, commenter := range pr.Commenters {
-		elements = append(elements, slack.NewRichTextSectionTextElement(
-			commenter.GetGitHubName(), &slack.RichTextSectionTextStyle{},
-		))
-		if idx > 0 && idx < commenterCount-1 {
+	elements := []slack.RichTextSectionElement{
+		slack.NewRichTextSectionTextElement(" - waiting on ", &slack.RichTextSectionTextStyle{}),
+	}
+	for idx, mention := range mentions {
+		if idx > 0 {
 			elements = append(elements, slack.NewRichTextSectionTextElement(
 				", ", &slack.RichTextSectionTextStyle{},
 			))
 		}
+		elements = append(elements, mention)
 	}
+	return elements
+}
 
-	return append(elements, slack.NewRichTextSectionTextElement(
-		")", &slack.RichTextSectionTextStyle{},
-	))
+var ciStatusIndicators = map[githubclient.CIStatus]string{
+	githubclient.CIStatusPassing: "✅",
+	githubclient.CIStatusFailing: "❌",
+	githubclient.CIStatusPending: "⏳",
 }
 
-func buildPRBulletPointBlock(pr prparser.PR) slack.RichTextElement {
-	titleAgeAndAuthorElements := []slack.RichTextSectionElement{
+func buildPRBulletPointBlock(pr prparser.PR, reviewerDisplay config.ReviewerDisplay) slack.RichTextElement {
+	elements := []slack.RichTextSectionElement{}
+	if indicator, ok := ciStatusIndicators[pr.CIStatus]; ok && pr.ShowCIStatus {
+		elements = append(elements, slack.NewRichTextSectionTextElement(
+			indicator+" ", &slack.RichTextSectionTextStyle{},
+		))
+	}
+	elements = append(elements,
 		slack.NewRichTextSectionLinkElement(pr.GetHTMLURL(), pr.GetTitle(), &slack.RichTextSectionTextStyle{Bold: true}),
+	)
+	if pr.SizeLabel != "" {
+		elements = append(elements,
+			slack.NewRichTextSectionTextElement(" ", &slack.RichTextSectionTextStyle{}),
+			slack.NewRichTextSectionTextElement(pr.SizeLabel, &slack.RichTextSectionTextStyle{Code: true}),
+		)
+	}
+	elements = append(elements,
 		slack.NewRichTextSectionTextElement(
 			" "+pr.GetPRAgeText(), &slack.RichTextSectionTextStyle{}),
 		slack.NewRichTextSectionTextElement(
 			" by ", &slack.RichTextSectionTextStyle{}),
 		getUserNameElement(pr),
-	}
+	)
+	elements = append(elements, getReviewersElements(pr, reviewerDisplay)...)
 	return slack.NewRichTextSection(
-		append(titleAgeAndAuthorElements, getReviewersElements(pr)...)...,
+		append(elements, getRequestedReviewersElements(pr, reviewerDisplay)...)...,
 	)
 }
 
-func makePRListBlock(openPRs []prparser.PR) *slack.RichTextBlock {
+func makePRListBlock(openPRs []prparser.PR, reviewerDisplay config.ReviewerDisplay) *slack.RichTextBlock {
 	var prBlocks []slack.RichTextElement
 	for _, pr := range openPRs {
-		prBlocks = append(prBlocks, buildPRBulletPointBlock(pr))
+		prBlocks = append(prBlocks, buildPRBulletPointBlock(pr, reviewerDisplay))
 	}
 	return slack.NewRichTextBlock(
 		"open_prs",
@@ -104,11 +203,42 @@ func makePRListBlock(openPRs []prparser.PR) *slack.RichTextBlock {
 	)
 }
 
-func addPRListBLock(blocks []slack.Block, heading string, prs []prparser.PR) []slack.Block {
-	return append(blocks, slack.NewHeaderBlock(
-		slack.NewTextBlockObject("plain_text", heading, false, false),
-	),
-		makePRListBlock(prs),
+func addPRListBLock(
+	blocks []slack.Block, category messagecontent.PRCategory, reviewerDisplay config.ReviewerDisplay,
+) []slack.Block {
+	blocks = append(blocks, slack.NewHeaderBlock(
+		slack.NewTextBlockObject("plain_text", category.Heading, false, false),
+	))
+	blocks = addMentionBlock(blocks, category)
+	if len(category.Sublists) == 0 {
+		return append(blocks, makePRListBlock(category.PRs, reviewerDisplay))
+	}
+	for _, sublist := range category.Sublists {
+		if len(sublist.PRs) > 0 {
+			blocks = append(blocks,
+				slack.NewContextBlock("",
+					slack.NewTextBlockObject("mrkdwn", "*"+sublist.Label+"*", false, false),
+				),
+				makePRListBlock(sublist.PRs, reviewerDisplay),
+			)
+		}
+	}
+	return blocks
+}
+
+// Adds a block mentioning the Slack user or user group of the category (if any).
+func addMentionBlock(blocks []slack.Block, category messagecontent.PRCategory) []slack.Block {
+	var mention string
+	switch {
+	case category.MentionSlackUserID != "":
+		mention = "<@" + category.MentionSlackUserID + ">"
+	case category.MentionSlackUserGroupID != "":
+		mention = "<!subteam^" + category.MentionSlackUserGroupID + ">"
+	default:
+		return blocks
+	}
+	return append(blocks,
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", mention, false, false), nil, nil),
 	)
 }
 
@@ -122,21 +252,67 @@ func addNoPRsBlock(blocks []slack.Block, noPRsText string) []slack.Block {
 	)
 }
 
+// Adds a block telling that the given number of PR lists were left out of the message.
+func addOmittedListsBlock(blocks []slack.Block, omittedListCount int) []slack.Block {
+	text := fmt.Sprintf("+%d more lists that do not fit in the message", omittedListCount)
+	if omittedListCount == 1 {
+		text = "+1 more list that does not fit in the message"
+	}
+	return append(blocks,
+		slack.NewContextBlock("omitted_lists_block",
+			slack.NewTextBlockObject("plain_text", text, false, false),
+		),
+	)
+}
+
+func addUnavailableRepositoriesBlock(blocks []slack.Block, repositories []string) []slack.Block {
+	if len(repositories) == 0 {
+		return blocks
+	}
+	text := "⚠️ Unable to fetch PRs from: " + strings.Join(repositories, ", ")
+	return append(blocks,
+		slack.NewContextBlock("unavailable_repositories_block",
+			slack.NewTextBlockObject("plain_text", text, false, false),
+		),
+	)
+}
+
+// Slack rejects messages with more blocks than this, so the PR lists that would exceed the
+// limit are left out of the message.
+const maxBlocks = 50
+
 func BuildMessage(content messagecontent.Content) (slack.Message, string) {
 	var blocks []slack.Block
 
 	if !content.HasPRs() {
 		blocks = addNoPRsBlock(blocks, content.SummaryText)
+		blocks = addUnavailableRepositoriesBlock(blocks, content.UnavailableRepositories)
 		return slack.NewBlockMessage(blocks...), content.SummaryText
 	}
 
-	if len(content.MainList) > 0 {
-		blocks = addPRListBLock(blocks, content.MainListHeading, content.MainList)
+	categories := slices.DeleteFunc(slices.Clone(content.Categories), func(category messagecontent.PRCategory) bool {
+		return len(category.PRs) == 0
+	})
+	maxListBlocks := maxBlocks
+	if len(content.UnavailableRepositories) > 0 {
+		maxListBlocks-- // leave room for the footer
 	}
-
-	if len(content.OldPRsList) > 0 {
-		blocks = addPRListBLock(blocks, content.OldPRsListHeading, content.OldPRsList)
+	for i, category := range categories {
+		categoryBlocks := addPRListBLock(
+			nil, category, cmp.Or(category.ReviewerDisplay, content.ReviewerDisplay),
+		)
+		limit := maxListBlocks
+		if i < len(categories)-1 {
+			limit-- // leave room for the omitted lists block in case the next lists do not fit
+		}
+		if len(blocks)+len(categoryBlocks) > limit {
+			blocks = addOmittedListsBlock(blocks, len(categories)-i)
+			break
+		}
+		blocks = append(blocks, categoryBlocks...)
 	}
 
+	blocks = addUnavailableRepositoriesBlock(blocks, content.UnavailableRepositories)
+
 	return slack.NewBlockMessage(blocks...), content.SummaryText
 }
@@ -1,6 +1,11 @@
 package messagebuilder_test
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -8,6 +13,7 @@ import (
 	"github.com/slack-go/slack"
 
 	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
 	"github.com/hellej/pr-slack-reminder-action/internal/messagebuilder"
 	"github.com/hellej/pr-slack-reminder-action/internal/messagecontent"
 	"github.com/hellej/pr-slack-reminder-action/internal/prparser"
@@ -40,9 +46,8 @@ func TestComposeSlackBlocksMessage(t *testing.T) {
 	t.Run("Message summary", func(t *testing.T) {
 		testPRs := getTestPRs()
 		content := messagecontent.Content{
-			SummaryText:     "1 open PRs are waiting for attention 👀",
-			MainListHeading: "🚀 New PRs since 1 days ago",
-			MainList:        testPRs.PRs,
+			SummaryText: "1 open PRs are waiting for attention 👀",
+			Categories:  getTestCategories(testPRs.PRs...),
 		}
 		_, got := messagebuilder.BuildMessage(content)
 		if got != content.SummaryText {
@@ -54,9 +59,8 @@ func TestComposeSlackBlocksMessage(t *testing.T) {
 		testPRs := getTestPRs()
 
 		content := messagecontent.Content{
-			SummaryText:     "1 open PRs are waiting for attention 👀",
-			MainListHeading: "🚀 New PRs since 1 days ago",
-			MainList:        testPRs.PRs,
+			SummaryText: "1 open PRs are waiting for attention 👀",
+			Categories:  getTestCategories(testPRs.PRs...),
 		}
 		got, _ := messagebuilder.BuildMessage(content)
 
@@ -64,8 +68,8 @@ func TestComposeSlackBlocksMessage(t *testing.T) {
 			t.Errorf("Expected non-empty blocks, got nil or empty")
 		}
 		headerBlock := got.Blocks.BlockSet[0].(*slack.HeaderBlock).Text
-		if headerBlock.Text != content.MainListHeading {
-			t.Errorf("Expected '%s', got '%s'", content.MainListHeading, headerBlock.Text)
+		if headerBlock.Text != content.Categories[0].Heading {
+			t.Errorf("Expected '%s', got '%s'", content.Categories[0].Heading, headerBlock.Text)
 		}
 		prBulletPointTextElements := got.Msg.Blocks.BlockSet[1].(*slack.RichTextBlock).Elements[0].(*slack.RichTextList).Elements[0].(*slack.RichTextSection).Elements
 		prLinkElement := prBulletPointTextElements[0].(*slack.RichTextSectionLinkElement)
@@ -87,6 +91,366 @@ func TestComposeSlackBlocksMessage(t *testing.T) {
 			t.Errorf("Expected text to be '%s', got '%s'", testPRs.PR1.Author.SlackUserID, prUserElement.UserID)
 		}
 	})
+
+	t.Run("Categories in order", func(t *testing.T) {
+		testPRs := getTestPRs()
+
+		content := messagecontent.Content{
+			SummaryText: "2 open PRs are waiting for attention 👀",
+			Categories: []messagecontent.PRCategory{
+				{Heading: "Ready to merge 🚢", PRs: []prparser.PR{testPRs.PR1}},
+				{Heading: "Empty category", PRs: []prparser.PR{}},
+				{Heading: "🚀 New PRs since 1 days ago", PRs: []prparser.PR{testPRs.PR1}},
+			},
+		}
+		got, _ := messagebuilder.BuildMessage(content)
+
+		if len(got.Blocks.BlockSet) != 4 {
+			t.Fatalf("Expected 4 blocks (two headings and lists), got %d", len(got.Blocks.BlockSet))
+		}
+		for i, expectedHeading := range []string{"Ready to merge 🚢", "🚀 New PRs since 1 days ago"} {
+			heading := got.Blocks.BlockSet[i*2].(*slack.HeaderBlock).Text.Text
+			if heading != expectedHeading {
+				t.Errorf("Expected heading %d to be '%s', got '%s'", i, expectedHeading, heading)
+			}
+		}
+	})
+
+	t.Run("Category with reviewer display override", func(t *testing.T) {
+		testPRs := getTestPRs()
+		testPRs.PR1.Approvers = []prparser.Collaborator{newTestCollaborator("alice")}
+
+		content := messagecontent.Content{
+			SummaryText: "1 open PR is waiting for attention 👀",
+			Categories: []messagecontent.PRCategory{{
+				Heading:         "Needs rebase 🔧",
+				PRs:             []prparser.PR{testPRs.PR1},
+				ReviewerDisplay: config.ReviewerDisplayNone,
+			}},
+			ReviewerDisplay: config.ReviewerDisplayMention,
+		}
+		got, _ := messagebuilder.BuildMessage(content)
+
+		if text := getPRBulletPointText(got, 0); !strings.HasSuffix(text, " 3 hours ago by ") {
+			t.Errorf("Expected only the author to be shown, got '%s'", text)
+		}
+	})
+
+	t.Run("Category with mention and sublists", func(t *testing.T) {
+		testPRs := getTestPRs()
+
+		content := messagecontent.Content{
+			SummaryText: "1 open PR is waiting for attention 👀",
+			Categories: []messagecontent.PRCategory{{
+				Heading:            "Test User (1)",
+				PRs:                []prparser.PR{testPRs.PR1},
+				MentionSlackUserID: "U12345678",
+				Sublists: []messagecontent.PRSublist{
+					{Label: "Waiting on you", PRs: []prparser.PR{}},
+					{Label: "Waiting on others", PRs: []prparser.PR{testPRs.PR1}},
+				},
+			}},
+		}
+		got, _ := messagebuilder.BuildMessage(content)
+
+		expectedBlockTypes := []slack.MessageBlockType{"header", "section", "context", "rich_text"}
+		if len(got.Blocks.BlockSet) != len(expectedBlockTypes) {
+			t.Fatalf("Expected %d blocks, got %d", len(expectedBlockTypes), len(got.Blocks.BlockSet))
+		}
+		for i, expectedType := range expectedBlockTypes {
+			if blockType := got.Blocks.BlockSet[i].BlockType(); blockType != expectedType {
+				t.Errorf("Expected block %d to be of type '%s', got '%s'", i, expectedType, blockType)
+			}
+		}
+		if mention := got.Blocks.BlockSet[1].(*slack.SectionBlock).Text.Text; mention != "<@U12345678>" {
+			t.Errorf("Expected mention '<@U12345678>', got '%s'", mention)
+		}
+		label := got.Blocks.BlockSet[2].(*slack.ContextBlock).ContextElements.Elements[0].(*slack.TextBlockObject)
+		if label.Text != "*Waiting on others*" {
+			t.Errorf("Expected sublist label '*Waiting on others*', got '%s'", label.Text)
+		}
+	})
+
+	t.Run("PR with unknown reviews", func(t *testing.T) {
+		testPRs := getTestPRs()
+		testPRs.PR1.ReviewsFetchError = errors.New("unable to fetch reviews")
+
+		content := messagecontent.Content{
+			SummaryText: "1 open PRs are waiting for attention 👀",
+			Categories:  getTestCategories(testPRs.PR1),
+		}
+		got, _ := messagebuilder.BuildMessage(content)
+
+		prBulletPointTextElements := got.Msg.Blocks.BlockSet[1].(*slack.RichTextBlock).Elements[0].(*slack.RichTextList).Elements[0].(*slack.RichTextSection).Elements
+		reviewsElement := prBulletPointTextElements[len(prBulletPointTextElements)-1].(*slack.RichTextSectionTextElement)
+		expectedReviewsText := " (reviews unknown)"
+		if reviewsElement.Text != expectedReviewsText {
+			t.Errorf("Expected text to be '%s', got '%s'", expectedReviewsText, reviewsElement.Text)
+		}
+	})
+
+	t.Run("PR with reviewer groups", func(t *testing.T) {
+		testPRs := getTestPRs()
+		testPRs.PR1.Approvers = []prparser.Collaborator{
+			newTestCollaborator("alice"), newTestCollaborator("bob"),
+		}
+		testPRs.PR1.StaleApprovers = []prparser.Collaborator{newTestCollaborator("erin")}
+		testPRs.PR1.ChangesRequesters = []prparser.Collaborator{newTestCollaborator("carol")}
+		testPRs.PR1.Commenters = []prparser.Collaborator{newTestCollaborator("dave")}
+
+		content := messagecontent.Content{
+			SummaryText: "1 open PRs are waiting for attention 👀",
+			Categories:  getTestCategories(testPRs.PR1),
+		}
+		got, _ := messagebuilder.BuildMessage(content)
+
+		expectedText := " (approved by alice, bob - erin approved an older revision - changes requested by carol - reviewed by dave)"
+		if text := getPRBulletPointText(got, 0); !strings.HasSuffix(text, expectedText) {
+			t.Errorf("Expected text to end with '%s', got '%s'", expectedText, text)
+		}
+	})
+
+	t.Run("PR with requested reviewers", func(t *testing.T) {
+		testPRs := getTestPRs()
+		alice := newTestCollaborator("alice")
+		alice.SlackUserID = "U2234567890"
+		testPRs.PR1.RequestedReviewers = []prparser.Collaborator{alice, newTestCollaborator("bob")}
+		testPRs.PR1.RequestedTeams = []prparser.Team{
+			{Team: &githubclient.Team{Slug: "platform-team"}, SlackUserGroupID: "S1234567890"},
+			{Team: &githubclient.Team{Slug: "search-team"}},
+		}
+
+		content := messagecontent.Content{
+			SummaryText:     "1 open PRs are waiting for attention 👀",
+			Categories:      getTestCategories(testPRs.PR1),
+			ReviewerDisplay: config.ReviewerDisplayMention,
+		}
+		got, _ := messagebuilder.BuildMessage(content)
+
+		elements := got.Msg.Blocks.BlockSet[1].(*slack.RichTextBlock).Elements[0].(*slack.RichTextList).Elements[0].(*slack.RichTextSection).Elements
+		waitingOnIndex := slices.IndexFunc(elements, func(e slack.RichTextSectionElement) bool {
+			textElement, ok := e.(*slack.RichTextSectionTextElement)
+			return ok && textElement.Text == " - waiting on "
+		})
+		if waitingOnIndex == -1 {
+			t.Fatalf("Expected the PR to be waiting on reviewers, got %+v", elements)
+		}
+		expectedElements := []slack.RichTextSectionElement{
+			slack.NewRichTextSectionUserElement(alice.SlackUserID, &slack.RichTextSectionTextStyle{}),
+			slack.NewRichTextSectionTextElement(", ", &slack.RichTextSectionTextStyle{}),
+			slack.NewRichTextSectionTextElement("bob", &slack.RichTextSectionTextStyle{}),
+			slack.NewRichTextSectionTextElement(", ", &slack.RichTextSectionTextStyle{}),
+			slack.NewRichTextSectionUserGroupElement("S1234567890"),
+			slack.NewRichTextSectionTextElement(", ", &slack.RichTextSectionTextStyle{}),
+			slack.NewRichTextSectionTextElement("search-team", &slack.RichTextSectionTextStyle{}),
+		}
+		gotElements := elements[waitingOnIndex+1:]
+		if len(gotElements) != len(expectedElements) {
+			t.Fatalf("Expected %d reviewer elements, got %d", len(expectedElements), len(gotElements))
+		}
+		for i, expected := range expectedElements {
+			if !reflect.DeepEqual(gotElements[i], expected) {
+				t.Errorf("Expected reviewer element %d to be %+v, got %+v", i, expected, gotElements[i])
+			}
+		}
+	})
+
+	t.Run("PR with size badge", func(t *testing.T) {
+		testPRs := getTestPRs()
+		testPRs.PR1.SizeLabel = "M"
+
+		content := messagecontent.Content{
+			SummaryText: "1 open PRs are waiting for attention 👀",
+			Categories:  getTestCategories(testPRs.PR1),
+		}
+		got, _ := messagebuilder.BuildMessage(content)
+
+		elements := got.Msg.Blocks.BlockSet[1].(*slack.RichTextBlock).Elements[0].(*slack.RichTextList).Elements[0].(*slack.RichTextSection).Elements
+		badgeElement := elements[2].(*slack.RichTextSectionTextElement)
+		if badgeElement.Text != "M" || badgeElement.Style == nil || !badgeElement.Style.Code {
+			t.Errorf("Expected a code styled 'M' badge after the title, got %+v", badgeElement)
+		}
+		expectedText := " M 3 hours ago by "
+		if text := getPRBulletPointText(got, 0); !strings.HasPrefix(text, expectedText) {
+			t.Errorf("Expected text to start with '%s', got '%s'", expectedText, text)
+		}
+	})
+
+	t.Run("PR with CI status", func(t *testing.T) {
+		testCases := []struct {
+			ciStatus     githubclient.CIStatus
+			expectedText string
+		}{
+			{githubclient.CIStatusPassing, "✅ "},
+			{githubclient.CIStatusFailing, "❌ "},
+			{githubclient.CIStatusPending, "⏳ "},
+			{githubclient.CIStatusNone, ""},
+			{githubclient.CIStatusUnknown, ""},
+		}
+		t.Run("indicators disabled", func(t *testing.T) {
+			testPRs := getTestPRs()
+			testPRs.PR1.CIStatus = githubclient.CIStatusPassing
+			content := messagecontent.Content{
+				SummaryText: "1 open PRs are waiting for attention 👀",
+				Categories:  getTestCategories(testPRs.PR1),
+			}
+			got, _ := messagebuilder.BuildMessage(content)
+			if text := getPRBulletPointText(got, 0); !strings.HasPrefix(text, " 3 hours ago") {
+				t.Errorf("Expected no CI status indicator, got '%s'", text)
+			}
+		})
+		for _, tc := range testCases {
+			testPRs := getTestPRs()
+			testPRs.PR1.CIStatus = tc.ciStatus
+			testPRs.PR1.ShowCIStatus = true
+
+			content := messagecontent.Content{
+				SummaryText: "1 open PRs are waiting for attention 👀",
+				Categories:  getTestCategories(testPRs.PR1),
+			}
+			got, _ := messagebuilder.BuildMessage(content)
+
+			if text := getPRBulletPointText(got, 0); !strings.HasPrefix(text, tc.expectedText+" 3 hours ago") {
+				t.Errorf("%s: expected text to start with '%s', got '%s'", tc.ciStatus, tc.expectedText, text)
+			}
+		}
+	})
+
+	t.Run("Lists that exceed the block limit of Slack", func(t *testing.T) {
+		testPRs := getTestPRs()
+		repositoryCategory := messagecontent.PRCategory{PRs: testPRs.PRs}
+		reviewerCategory := messagecontent.PRCategory{
+			PRs:                testPRs.PRs,
+			ReviewerDisplay:    config.ReviewerDisplayName,
+			MentionSlackUserID: "U12345678",
+		}
+		authorCategory := messagecontent.PRCategory{
+			PRs:                testPRs.PRs,
+			MentionSlackUserID: "U12345678",
+			Sublists: []messagecontent.PRSublist{
+				{Label: "Waiting on you", PRs: testPRs.PRs},
+				{Label: "Waiting on others", PRs: testPRs.PRs},
+			},
+		}
+		testCases := []struct {
+			name                    string
+			category                messagecontent.PRCategory // repeated with numbered headings
+			categoryCount           int
+			unavailableRepositories []string
+			expectedHeadingCount    int
+			expectedOmittedText     string
+		}{
+			{"all lists fit", repositoryCategory, 25, nil, 25, ""},
+			{"lists omitted", repositoryCategory, 30, nil, 24, "+6 more lists that do not fit in the message"},
+			{"lists omitted before footer", repositoryCategory, 25, []string{"some-org/repo"}, 24, "+1 more list that does not fit in the message"},
+			{"reviewer lists omitted", reviewerCategory, 20, nil, 16, "+4 more lists that do not fit in the message"},
+			{"author lists omitted", authorCategory, 10, nil, 8, "+2 more lists that do not fit in the message"},
+		}
+		for _, tc := range testCases {
+			content := messagecontent.Content{
+				SummaryText:             "1 open PRs are waiting for attention 👀",
+				UnavailableRepositories: tc.unavailableRepositories,
+			}
+			for i := range tc.categoryCount {
+				category := tc.category
+				category.Heading = fmt.Sprintf("Group %d (1)", i)
+				content.Categories = append(content.Categories, category)
+			}
+			got, _ := messagebuilder.BuildMessage(content)
+
+			blocks := got.Blocks.BlockSet
+			if len(blocks) > 50 {
+				t.Errorf("%s: expected at most 50 blocks, got %d", tc.name, len(blocks))
+			}
+			headingCount := 0
+			omittedText := ""
+			for _, block := range blocks {
+				switch block := block.(type) {
+				case *slack.HeaderBlock:
+					headingCount++
+				case *slack.ContextBlock:
+					if block.BlockID == "omitted_lists_block" {
+						omittedText = block.ContextElements.Elements[0].(*slack.TextBlockObject).Text
+					}
+				}
+			}
+			if headingCount != tc.expectedHeadingCount {
+				t.Errorf("%s: expected %d lists, got %d", tc.name, tc.expectedHeadingCount, headingCount)
+			}
+			if omittedText != tc.expectedOmittedText {
+				t.Errorf("%s: expected omitted lists text '%s', got '%s'", tc.name, tc.expectedOmittedText, omittedText)
+			}
+			if len(tc.unavailableRepositories) > 0 && blocks[len(blocks)-1].(*slack.ContextBlock).BlockID != "unavailable_repositories_block" {
+				t.Errorf("%s: expected the unavailable repositories footer to be the last block", tc.name)
+			}
+		}
+	})
+
+	t.Run("Reviewer display modes", func(t *testing.T) {
+		testCases := []struct {
+			reviewerDisplay      config.ReviewerDisplay
+			expectedText         string
+			expectedMentionCount int
+		}{
+			{config.ReviewerDisplayMention, " (approved by ) - waiting on ", 2},
+			{config.ReviewerDisplayName, " (approved by Alice) - waiting on bob", 0},
+			{config.ReviewerDisplayNone, "", 0},
+		}
+		for _, tc := range testCases {
+			testPRs := getTestPRs()
+			alice := newTestCollaborator("alice")
+			alice.Name = "Alice"
+			alice.SlackUserID = "U2234567890"
+			bob := newTestCollaborator("bob")
+			bob.SlackUserID = "U3234567890"
+			testPRs.PR1.Approvers = []prparser.Collaborator{alice}
+			testPRs.PR1.RequestedReviewers = []prparser.Collaborator{bob}
+
+			content := messagecontent.Content{
+				SummaryText:     "1 open PRs are waiting for attention 👀",
+				Categories:      getTestCategories(testPRs.PR1),
+				ReviewerDisplay: tc.reviewerDisplay,
+			}
+			got, _ := messagebuilder.BuildMessage(content)
+
+			text := getPRBulletPointText(got, 0)
+			if !strings.HasSuffix(text, " by "+tc.expectedText) {
+				t.Errorf("%s: expected text to end with '%s', got '%s'", tc.reviewerDisplay, tc.expectedText, text)
+			}
+			elements := got.Msg.Blocks.BlockSet[1].(*slack.RichTextBlock).Elements[0].(*slack.RichTextList).Elements[0].(*slack.RichTextSection).Elements
+			mentionCount := 0
+			for _, element := range elements[4:] { // skip the title, age and author elements
+				if _, ok := element.(*slack.RichTextSectionUserElement); ok {
+					mentionCount++
+				}
+			}
+			if mentionCount != tc.expectedMentionCount {
+				t.Errorf("%s: expected %d reviewer mentions, got %d", tc.reviewerDisplay, tc.expectedMentionCount, mentionCount)
+			}
+		}
+	})
+}
+
+func newTestCollaborator(login string) prparser.Collaborator {
+	return prparser.Collaborator{Collaborator: &githubclient.Collaborator{Login: login}}
+}
+
+// Concatenates the texts of the text elements of the PR bullet point at the given index
+// (of the first PR list of the message).
+func getPRBulletPointText(message slack.Message, index int) string {
+	prList := message.Msg.Blocks.BlockSet[1].(*slack.RichTextBlock).Elements[0].(*slack.RichTextList)
+	text := ""
+	for _, element := range prList.Elements[index].(*slack.RichTextSection).Elements {
+		if textElement, ok := element.(*slack.RichTextSectionTextElement); ok {
+			text += textElement.Text
+		}
+	}
+	return text
+}
+
+// Returns a single category (with heading "🚀 New PRs since 1 days ago") of the PRs.
+func getTestCategories(prs ...prparser.PR) []messagecontent.PRCategory {
+	return []messagecontent.PRCategory{{Heading: "🚀 New PRs since 1 days ago", PRs: prs}}
 }
 
 type TestPRs struct {
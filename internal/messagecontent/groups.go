@@ -0,0 +1,201 @@
+package messagecontent
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
+	"github.com/hellej/pr-slack-reminder-action/internal/prparser"
+)
+
+// Returns the categories of the PRs grouped as configured (GroupByNone is not handled here).
+func getGroupCategories(openPRs []prparser.PR, contentInputs config.ContentInputs) []PRCategory {
+	switch contentInputs.GroupBy {
+	case config.GroupByRepository:
+		return getRepositoryCategories(openPRs)
+	case config.GroupByLabel:
+		return getLabelGroupCategories(openPRs, contentInputs.LabelGroups, contentInputs.UngroupedHeading)
+	case config.GroupByReviewer:
+		return getReviewerCategories(openPRs, contentInputs)
+	case config.GroupByAuthor:
+		return getAuthorCategories(openPRs)
+	}
+	return nil
+}
+
+// Returns one category per repository (sorted by the repository path) with the repository
+// path and its PR count as the heading.
+func getRepositoryCategories(openPRs []prparser.PR) []PRCategory {
+	prsByRepository := map[string][]prparser.PR{}
+	for _, pr := range openPRs {
+		repository := pr.GetRepositoryPath()
+		prsByRepository[repository] = append(prsByRepository[repository], pr)
+	}
+	repositories := slices.Sorted(maps.Keys(prsByRepository))
+
+	categories := make([]PRCategory, len(repositories))
+	for i, repository := range repositories {
+		prs := prsByRepository[repository]
+		categories[i] = PRCategory{Heading: getGroupHeading(repository, len(prs)), PRs: prs}
+	}
+	return categories
+}
+
+// Returns one category per label group with PRs (in the configured order) and the category
+// of ungrouped PRs (if any) last. PRs with labels of several groups are listed in each of them.
+func getLabelGroupCategories(
+	openPRs []prparser.PR, labelGroups []config.LabelGroup, ungroupedHeading string,
+) []PRCategory {
+	prsByGroup := make([][]prparser.PR, len(labelGroups))
+	ungroupedPRs := []prparser.PR{}
+	for _, pr := range openPRs {
+		labels := getLabelNames(pr)
+		grouped := false
+		for i, group := range labelGroups {
+			if group.MatchesAnyLabel(labels) {
+				prsByGroup[i] = append(prsByGroup[i], pr)
+				grouped = true
+			}
+		}
+		if !grouped {
+			ungroupedPRs = append(ungroupedPRs, pr)
+		}
+	}
+
+	categories := make([]PRCategory, 0, len(labelGroups)+1)
+	for i, group := range labelGroups {
+		if len(prsByGroup[i]) > 0 {
+			categories = append(categories, PRCategory{
+				Heading: getGroupHeading(group.Name, len(prsByGroup[i])), PRs: prsByGroup[i],
+			})
+		}
+	}
+	return appendUngroupedCategory(categories, ungroupedHeading, ungroupedPRs)
+}
+
+// Appends the category of the PRs that are not in any group if there are such PRs.
+func appendUngroupedCategory(
+	categories []PRCategory, ungroupedHeading string, ungroupedPRs []prparser.PR,
+) []PRCategory {
+	if len(ungroupedPRs) == 0 {
+		return categories
+	}
+	return append(categories, PRCategory{
+		Heading: getGroupHeading(ungroupedHeading, len(ungroupedPRs)), PRs: ungroupedPRs,
+	})
+}
+
+// Returns one category per requested reviewer (users first, then teams, both sorted by name)
+// listing the PRs waiting on their review, and the category of PRs without requested
+// reviewers (if any) last. If reviewers are mentioned, each reviewer is mentioned once at the top of
+// their list instead of in the list items.
+func getReviewerCategories(openPRs []prparser.PR, contentInputs config.ContentInputs) []PRCategory {
+	mention := contentInputs.ReviewerDisplay == config.ReviewerDisplayMention
+	userCategories := map[string]*PRCategory{}
+	teamCategories := map[string]*PRCategory{}
+	ungroupedPRs := []prparser.PR{}
+	for _, pr := range openPRs {
+		for _, reviewer := range pr.RequestedReviewers {
+			if _, ok := userCategories[reviewer.Login]; !ok {
+				userCategories[reviewer.Login] = &PRCategory{
+					Heading: reviewer.GetGitHubName(), MentionSlackUserID: reviewer.SlackUserID,
+				}
+			}
+			userCategories[reviewer.Login].PRs = append(userCategories[reviewer.Login].PRs, pr)
+		}
+		for _, team := range pr.RequestedTeams {
+			if _, ok := teamCategories[team.Slug]; !ok {
+				teamCategories[team.Slug] = &PRCategory{
+					Heading: cmp.Or(team.Name, team.Slug), MentionSlackUserGroupID: team.SlackUserGroupID,
+				}
+			}
+			teamCategories[team.Slug].PRs = append(teamCategories[team.Slug].PRs, pr)
+		}
+		if len(pr.RequestedReviewers) == 0 && len(pr.RequestedTeams) == 0 {
+			ungroupedPRs = append(ungroupedPRs, pr)
+		}
+	}
+
+	categories := []PRCategory{}
+	for _, categoriesByName := range []map[string]*PRCategory{userCategories, teamCategories} {
+		sorted := slices.SortedFunc(maps.Values(categoriesByName), func(a, b *PRCategory) int {
+			return strings.Compare(a.Heading, b.Heading)
+		})
+		for _, category := range sorted {
+			category.Heading = getGroupHeading(category.Heading, len(category.PRs))
+			if mention {
+				category.ReviewerDisplay = config.ReviewerDisplayName
+			} else {
+				category.MentionSlackUserID, category.MentionSlackUserGroupID = "", ""
+			}
+			categories = append(categories, *category)
+		}
+	}
+	return appendUngroupedCategory(categories, contentInputs.UngroupedHeading, ungroupedPRs)
+}
+
+const (
+	waitingOnAuthorLabel = "Waiting on you"
+	waitingOnOthersLabel = "Waiting on others"
+)
+
+// The author needs to act if changes have been requested, the PR has merge conflicts or
+// its CI checks fail. Otherwise the PR is waiting on the reviewers (or on being merged).
+func isWaitingOnAuthor(pr prparser.PR) bool {
+	return len(pr.ChangesRequesters) > 0 ||
+		pr.HasMergeConflicts() ||
+		pr.CIStatus == githubclient.CIStatusFailing
+}
+
+// Returns one category per author (sorted by name) with the author mentioned at the top.
+// The PRs of each author are split into the PRs waiting on the author and the PRs waiting
+// on others.
+func getAuthorCategories(openPRs []prparser.PR) []PRCategory {
+	categoriesByAuthor := map[string]*PRCategory{}
+	for _, pr := range openPRs {
+		category, ok := categoriesByAuthor[pr.Author.Login]
+		if !ok {
+			category = &PRCategory{
+				Heading:            pr.Author.GetGitHubName(),
+				MentionSlackUserID: pr.Author.SlackUserID,
+				Sublists: []PRSublist{
+					{Label: waitingOnAuthorLabel, PRs: []prparser.PR{}},
+					{Label: waitingOnOthersLabel, PRs: []prparser.PR{}},
+				},
+			}
+			categoriesByAuthor[pr.Author.Login] = category
+		}
+		category.PRs = append(category.PRs, pr)
+		sublist := &category.Sublists[1]
+		if isWaitingOnAuthor(pr) {
+			sublist = &category.Sublists[0]
+		}
+		sublist.PRs = append(sublist.PRs, pr)
+	}
+
+	sorted := slices.SortedFunc(maps.Values(categoriesByAuthor), func(a, b *PRCategory) int {
+		return strings.Compare(a.Heading, b.Heading)
+	})
+	categories := make([]PRCategory, len(sorted))
+	for i, category := range sorted {
+		category.Heading = getGroupHeading(category.Heading, len(category.PRs))
+		categories[i] = *category
+	}
+	return categories
+}
+
+func getLabelNames(pr prparser.PR) []string {
+	names := make([]string, len(pr.Labels))
+	for i, label := range pr.Labels {
+		names[i] = label.GetName()
+	}
+	return names
+}
+
+func getGroupHeading(name string, prCount int) string {
+	return fmt.Sprintf("%s (%d)", name, prCount)
+}
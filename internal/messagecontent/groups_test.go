@@ -0,0 +1,115 @@
+package messagecontent_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v72/github"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
+	"github.com/hellej/pr-slack-reminder-action/internal/messagecontent"
+	"github.com/hellej/pr-slack-reminder-action/internal/prparser"
+)
+
+func newTestPR(number int, labels ...string) prparser.PR {
+	githubLabels := make([]*github.Label, len(labels))
+	for i, label := range labels {
+		githubLabels[i] = &github.Label{Name: github.Ptr(label)}
+	}
+	return prparser.PR{
+		PR: &githubclient.PR{
+			PullRequest: &github.PullRequest{Number: github.Ptr(number), Labels: githubLabels},
+		},
+		Author: prparser.NewCollaborator(&githubclient.Collaborator{Login: "alice"}, ""),
+	}
+}
+
+func getHeadings(content messagecontent.Content) []string {
+	headings := make([]string, len(content.Categories))
+	for i, category := range content.Categories {
+		headings[i] = category.Heading
+	}
+	return headings
+}
+
+func TestGetContentLabelGroups(t *testing.T) {
+	paymentsPatterns, err := config.NewPatterns("team:payments")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	searchPatterns, err := config.NewPatterns("team:search")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	contentInputs := config.ContentInputs{
+		GroupBy: config.GroupByLabel,
+		LabelGroups: []config.LabelGroup{
+			{Name: "Search", Labels: searchPatterns},
+			{Name: "Payments", Labels: paymentsPatterns},
+		},
+		UngroupedHeading: "Ungrouped",
+	}
+
+	testCases := []struct {
+		name             string
+		prs              []prparser.PR
+		expectedHeadings []string
+	}{
+		{
+			name:             "empty group and no ungrouped PRs",
+			prs:              []prparser.PR{newTestPR(1, "team:payments")},
+			expectedHeadings: []string{"Payments (1)"},
+		},
+		{
+			name:             "empty group and ungrouped PRs",
+			prs:              []prparser.PR{newTestPR(1, "team:payments"), newTestPR(2, "bug")},
+			expectedHeadings: []string{"Payments (1)", "Ungrouped (1)"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content := messagecontent.GetContent(tc.prs, nil, contentInputs)
+			if headings := getHeadings(content); !slices.Equal(headings, tc.expectedHeadings) {
+				t.Errorf("Expected headings %v, got %v", tc.expectedHeadings, headings)
+			}
+		})
+	}
+}
+
+func TestGetContentReviewerGroups(t *testing.T) {
+	withReviewer := func(pr prparser.PR, login string) prparser.PR {
+		pr.RequestedReviewers = []prparser.Collaborator{
+			prparser.NewCollaborator(&githubclient.Collaborator{Login: login}, ""),
+		}
+		return pr
+	}
+	contentInputs := config.ContentInputs{GroupBy: config.GroupByReviewer, UngroupedHeading: "Ungrouped"}
+
+	testCases := []struct {
+		name             string
+		prs              []prparser.PR
+		expectedHeadings []string
+	}{
+		{
+			name:             "no PRs without requested reviewers",
+			prs:              []prparser.PR{withReviewer(newTestPR(1), "bob")},
+			expectedHeadings: []string{"bob (1)"},
+		},
+		{
+			name:             "PRs without requested reviewers",
+			prs:              []prparser.PR{withReviewer(newTestPR(1), "bob"), newTestPR(2)},
+			expectedHeadings: []string{"bob (1)", "Ungrouped (1)"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content := messagecontent.GetContent(tc.prs, nil, contentInputs)
+			if headings := getHeadings(content); !slices.Equal(headings, tc.expectedHeadings) {
+				t.Errorf("Expected headings %v, got %v", tc.expectedHeadings, headings)
+			}
+		})
+	}
+}
@@ -6,20 +6,26 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
 	"github.com/hellej/pr-slack-reminder-action/internal/config"
 	"github.com/hellej/pr-slack-reminder-action/internal/prparser"
 )
 
 type Content struct {
-	SummaryText       string
-	MainListHeading   string
-	MainList          []prparser.PR
-	OldPRsListHeading string
-	OldPRsList        []prparser.PR
+	SummaryText string
+	// PR lists in the order they are shown in the message (empty categories are not shown)
+	Categories []PRCategory
+	// Repositories (owner/repo) from which PRs could not be fetched
+	UnavailableRepositories []string
+	ReviewerDisplay         config.ReviewerDisplay
 }
 
 func (c Content) GetPRCount() int {
-	return len(c.MainList) + len(c.OldPRsList)
+	count := 0
+	for _, category := range c.Categories {
+		count += len(category.PRs)
+	}
+	return count
 }
 
 func (c Content) HasPRs() bool {
@@ -29,6 +35,25 @@ func (c Content) HasPRs() bool {
 type PRCategory struct {
 	Heading string
 	PRs     []prparser.PR
+	// Overrides the reviewer display of the content for this category if set
+	ReviewerDisplay config.ReviewerDisplay
+	// Slack user or user group to mention once at the top of the list (e.g. the reviewer
+	// the PRs are waiting on), empty if none
+	MentionSlackUserID      string
+	MentionSlackUserGroupID string
+	// If set, the PRs of the category are listed in these sublists under the heading instead
+	// of a single list (PRs still contains all PRs of the category)
+	Sublists []PRSublist
+}
+
+type PRSublist struct {
+	Label string
+	PRs   []prparser.PR
+}
+
+// Returns a category with the <pr_count> placeholder of the heading replaced.
+func newPRCategory(heading string, prs []prparser.PR) PRCategory {
+	return PRCategory{Heading: formatListHeading(heading, len(prs)), PRs: prs}
 }
 
 func getNewAndOldPRs(openPRs []prparser.PR, oldPRThresholdHours int) ([]prparser.PR, []prparser.PR) {
@@ -36,7 +61,7 @@ func getNewAndOldPRs(openPRs []prparser.PR, oldPRThresholdHours int) ([]prparser
 	oldPRsList := []prparser.PR{}
 
 	for _, pr := range openPRs {
-		if pr.GetCreatedAt().After(time.Now().Add(-time.Duration(oldPRThresholdHours) * time.Hour)) {
+		if pr.GetAge() < time.Duration(oldPRThresholdHours)*time.Hour {
 			mainList = append(mainList, pr)
 		} else {
 			oldPRsList = append(oldPRsList, pr)
@@ -49,6 +74,8 @@ func formatListHeading(heading string, prCount int) string {
 	return strings.ReplaceAll(heading, "<pr_count>", strconv.Itoa(prCount))
 }
 
+const noPRsFromAvailableRepositoriesText = "No open PRs found in the repositories that could be read"
+
 func getSummaryText(prCount int) string {
 	if prCount == 1 {
 		return "1 open PR is waiting for attention 👀"
@@ -56,26 +83,148 @@ func getSummaryText(prCount int) string {
 	return fmt.Sprintf("%d open PRs are waiting for attention 👀", prCount)
 }
 
-func GetContent(openPRs []prparser.PR, contentInputs config.ContentInputs) Content {
-	switch {
-	case len(openPRs) == 0:
-		return Content{
-			SummaryText: contentInputs.NoPRsMessage,
+func splitDraftPRs(openPRs []prparser.PR) ([]prparser.PR, []prparser.PR) {
+	readyPRs := []prparser.PR{}
+	draftPRs := []prparser.PR{}
+	for _, pr := range openPRs {
+		if pr.GetDraft() {
+			draftPRs = append(draftPRs, pr)
+		} else {
+			readyPRs = append(readyPRs, pr)
+		}
+	}
+	return readyPRs, draftPRs
+}
+
+// A PR is ready to merge if it is not a draft, has enough approvals of its latest revision
+// and no change requests, its CI checks pass (or the repository has no CI) and it has no
+// merge conflicts.
+func isReadyToMerge(pr prparser.PR, requiredApprovals int) bool {
+	return !pr.GetDraft() &&
+		pr.HasReviewInfo() &&
+		len(pr.Approvers) >= requiredApprovals &&
+		len(pr.ChangesRequesters) == 0 &&
+		(pr.CIStatus == githubclient.CIStatusPassing || pr.CIStatus == githubclient.CIStatusNone) &&
+		pr.Mergeable != nil && *pr.Mergeable
+}
+
+func splitReadyToMergePRs(openPRs []prparser.PR, requiredApprovals int) ([]prparser.PR, []prparser.PR) {
+	readyToMergePRs := []prparser.PR{}
+	otherPRs := []prparser.PR{}
+	for _, pr := range openPRs {
+		if isReadyToMerge(pr, requiredApprovals) {
+			readyToMergePRs = append(readyToMergePRs, pr)
+		} else {
+			otherPRs = append(otherPRs, pr)
+		}
+	}
+	return readyToMergePRs, otherPRs
+}
+
+// Draft PRs with merge conflicts are not included as they are not expected to be mergeable.
+func splitNeedsRebasePRs(openPRs []prparser.PR) ([]prparser.PR, []prparser.PR) {
+	needsRebasePRs := []prparser.PR{}
+	otherPRs := []prparser.PR{}
+	for _, pr := range openPRs {
+		if pr.HasMergeConflicts() && !pr.GetDraft() {
+			needsRebasePRs = append(needsRebasePRs, pr)
+		} else {
+			otherPRs = append(otherPRs, pr)
 		}
-	case contentInputs.OldPRThresholdHours == nil:
-		return Content{
-			SummaryText:     getSummaryText(len(openPRs)),
-			MainListHeading: formatListHeading(contentInputs.MainListHeading, len(openPRs)),
-			MainList:        openPRs,
+	}
+	return needsRebasePRs, otherPRs
+}
+
+// Splits the PRs into the configured age categories (sorted by min age).
+func getAgeCategories(openPRs []prparser.PR, ageCategories []config.AgeCategory) []PRCategory {
+	prsByCategory := make([][]prparser.PR, len(ageCategories))
+	for _, pr := range openPRs {
+		ageHours := pr.GetAge().Hours()
+		for i := len(ageCategories) - 1; i >= 0; i-- {
+			if ageHours >= float64(ageCategories[i].MinAgeHours) || i == 0 {
+				prsByCategory[i] = append(prsByCategory[i], pr)
+				break
+			}
 		}
-	default:
-		newPRs, oldPRs := getNewAndOldPRs(openPRs, *contentInputs.OldPRThresholdHours)
-		return Content{
-			SummaryText:       getSummaryText(len(newPRs) + len(oldPRs)),
-			MainListHeading:   formatListHeading(contentInputs.MainListHeading, len(openPRs)),
-			MainList:          newPRs,
-			OldPRsListHeading: formatListHeading(contentInputs.OldPRsListHeading, len(oldPRs)),
-			OldPRsList:        oldPRs,
+	}
+	categories := make([]PRCategory, len(ageCategories))
+	for i, ageCategory := range ageCategories {
+		categories[i] = newPRCategory(ageCategory.Heading, prsByCategory[i])
+	}
+	return categories
+}
+
+// Returns the main list (with the total PR count in its heading) and, if a threshold is
+// configured, the old PRs list.
+func getMainAndOldPRsCategories(
+	openPRs []prparser.PR, totalPRCount int, contentInputs config.ContentInputs,
+) []PRCategory {
+	mainListHeading := formatListHeading(contentInputs.MainListHeading, totalPRCount)
+	if contentInputs.OldPRThresholdHours == nil {
+		return []PRCategory{{Heading: mainListHeading, PRs: openPRs}}
+	}
+	mainList, oldPRsList := getNewAndOldPRs(openPRs, *contentInputs.OldPRThresholdHours)
+	return []PRCategory{
+		{Heading: mainListHeading, PRs: mainList},
+		newPRCategory(contentInputs.OldPRsListHeading, oldPRsList),
+	}
+}
+
+func GetContent(
+	openPRs []prparser.PR,
+	unavailableRepositories []string,
+	contentInputs config.ContentInputs,
+) Content {
+	content := Content{
+		UnavailableRepositories: unavailableRepositories,
+		ReviewerDisplay:         contentInputs.ReviewerDisplay,
+	}
+	if len(openPRs) == 0 {
+		content.SummaryText = contentInputs.NoPRsMessage
+		if content.SummaryText == "" && len(unavailableRepositories) > 0 {
+			// The message is sent even without no-prs-message so that the errors are not hidden
+			content.SummaryText = noPRsFromAvailableRepositoriesText
 		}
+		return content
+	}
+	content.SummaryText = getSummaryText(len(openPRs))
+
+	prs := openPRs
+	var readyToMergePRs, needsRebasePRs, draftPRs []prparser.PR
+	if contentInputs.ReadyToMergeListHeading != "" {
+		readyToMergePRs, prs = splitReadyToMergePRs(prs, contentInputs.ReadyToMergeApprovals)
+		content.Categories = append(content.Categories,
+			newPRCategory(contentInputs.ReadyToMergeListHeading, readyToMergePRs),
+		)
+	}
+	if contentInputs.NeedsRebaseListHeading != "" {
+		needsRebasePRs, prs = splitNeedsRebasePRs(prs)
+	}
+	if contentInputs.DraftPRsListHeading != "" {
+		prs, draftPRs = splitDraftPRs(prs)
+	}
+
+	if contentInputs.GroupBy != "" && contentInputs.GroupBy != config.GroupByNone {
+		content.Categories = append(content.Categories, getGroupCategories(prs, contentInputs)...)
+	} else if len(contentInputs.AgeCategories) > 0 {
+		content.Categories = append(content.Categories, getAgeCategories(prs, contentInputs.AgeCategories)...)
+	} else {
+		content.Categories = append(content.Categories,
+			getMainAndOldPRsCategories(prs, len(openPRs), contentInputs)...,
+		)
+	}
+
+	if contentInputs.NeedsRebaseListHeading != "" {
+		needsRebaseCategory := newPRCategory(contentInputs.NeedsRebaseListHeading, needsRebasePRs)
+		// addressed to the authors, so reviewers are not shown (nor mentioned)
+		needsRebaseCategory.ReviewerDisplay = config.ReviewerDisplayNone
+		content.Categories = append(content.Categories, needsRebaseCategory)
+	}
+	if contentInputs.DraftPRsListHeading != "" {
+		content.Categories = append(content.Categories,
+			newPRCategory(contentInputs.DraftPRsListHeading, draftPRs),
+		)
 	}
+	sortCategories(content.Categories, contentInputs.SortBy)
+	return content
 }
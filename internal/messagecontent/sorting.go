@@ -0,0 +1,71 @@
+package messagecontent
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
+	"github.com/hellej/pr-slack-reminder-action/internal/prparser"
+)
+
+// Returns a function comparing PRs in the given order. The sort is stable, so PRs that are
+// equal in the order keep the order of prparser.ParsePRs (newest first by age basis).
+func getPRComparator(sortBy config.SortBy) func(a, b prparser.PR) int {
+	switch sortBy {
+	case config.SortByOldest:
+		return func(a, b prparser.PR) int {
+			return a.GetAgeStartTime(a.AgeBasis).Compare(b.GetAgeStartTime(b.AgeBasis))
+		}
+	case config.SortByUpdated:
+		return func(a, b prparser.PR) int {
+			return b.GetUpdatedAt().Time.Compare(a.GetUpdatedAt().Time)
+		}
+	case config.SortByApprovals:
+		return func(a, b prparser.PR) int {
+			return cmp.Compare(b.GetApprovalCount(), a.GetApprovalCount())
+		}
+	case config.SortBySize:
+		return func(a, b prparser.PR) int {
+			if a.Size == nil || b.Size == nil {
+				return compareNil(a.Size == nil, b.Size == nil)
+			}
+			return cmp.Compare(a.Size.LinesChanged(), b.Size.LinesChanged())
+		}
+	case config.SortByRepository:
+		return func(a, b prparser.PR) int {
+			return strings.Compare(a.GetRepositoryPath(), b.GetRepositoryPath())
+		}
+	}
+	return func(a, b prparser.PR) int {
+		return b.GetAgeStartTime(b.AgeBasis).Compare(a.GetAgeStartTime(a.AgeBasis))
+	}
+}
+
+// Orders the unknown (nil) values last.
+func compareNil(aIsNil bool, bIsNil bool) int {
+	switch {
+	case aIsNil == bIsNil:
+		return 0
+	case aIsNil:
+		return 1
+	}
+	return -1
+}
+
+// Sorts the PRs of each category (and their sublists) in the given order. The PR slices are
+// copied as categories may share them with the input PRs.
+func sortCategories(categories []PRCategory, sortBy config.SortBy) {
+	compare := getPRComparator(sortBy)
+	sorted := func(prs []prparser.PR) []prparser.PR {
+		prs = slices.Clone(prs)
+		slices.SortStableFunc(prs, compare)
+		return prs
+	}
+	for i := range categories {
+		categories[i].PRs = sorted(categories[i].PRs)
+		for j := range categories[i].Sublists {
+			categories[i].Sublists[j].PRs = sorted(categories[i].Sublists[j].PRs)
+		}
+	}
+}
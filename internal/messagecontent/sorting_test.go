@@ -0,0 +1,76 @@
+package messagecontent_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
+	"github.com/hellej/pr-slack-reminder-action/internal/messagecontent"
+	"github.com/hellej/pr-slack-reminder-action/internal/prparser"
+)
+
+func newSortingTestPR(
+	number int, repository string, createdHoursAgo int, updatedHoursAgo int, approvals int, linesChanged *int,
+) prparser.PR {
+	now := time.Now()
+	pr := newTestPR(number)
+	pr.Repository = repository
+	pr.Owner = "some-org"
+	pr.CreatedAt = &github.Timestamp{Time: now.Add(-time.Duration(createdHoursAgo) * time.Hour)}
+	pr.UpdatedAt = &github.Timestamp{Time: now.Add(-time.Duration(updatedHoursAgo) * time.Hour)}
+	for range approvals {
+		pr.Approvers = append(pr.Approvers,
+			prparser.NewCollaborator(&githubclient.Collaborator{Login: "bob"}, ""),
+		)
+	}
+	if linesChanged != nil {
+		pr.Size = &githubclient.PRSize{Additions: *linesChanged}
+	}
+	return pr
+}
+
+func getPRNumbers(prs []prparser.PR) []int {
+	numbers := make([]int, len(prs))
+	for i, pr := range prs {
+		numbers[i] = pr.GetNumber()
+	}
+	return numbers
+}
+
+func TestGetContentSorting(t *testing.T) {
+	// In the order of prparser.ParsePRs (newest first)
+	prs := []prparser.PR{
+		newSortingTestPR(1, "repo-b", 1, 5, 0, github.Ptr(300)),
+		newSortingTestPR(2, "repo-a", 2, 1, 1, nil),
+		newSortingTestPR(3, "repo-c", 3, 3, 2, github.Ptr(10)),
+		newSortingTestPR(4, "repo-a", 4, 2, 2, nil),
+	}
+
+	testCases := []struct {
+		sortBy          config.SortBy
+		expectedPROrder []int
+	}{
+		{config.SortByNewest, []int{1, 2, 3, 4}},
+		{config.SortByOldest, []int{4, 3, 2, 1}},
+		{config.SortByUpdated, []int{2, 4, 3, 1}},
+		{config.SortByApprovals, []int{3, 4, 2, 1}},
+		{config.SortBySize, []int{3, 1, 2, 4}}, // PRs of unknown size last
+		{config.SortByRepository, []int{2, 4, 1, 3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.sortBy), func(t *testing.T) {
+			content := messagecontent.GetContent(prs, nil, config.ContentInputs{
+				MainListHeading: "Open PRs (<pr_count>)",
+				SortBy:          tc.sortBy,
+			})
+			if order := getPRNumbers(content.Categories[0].PRs); !slices.Equal(order, tc.expectedPROrder) {
+				t.Errorf("Expected PR order %v, got %v", tc.expectedPROrder, order)
+			}
+		})
+	}
+}
@@ -7,13 +7,28 @@ import (
 	"time"
 
 	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
+	"github.com/hellej/pr-slack-reminder-action/internal/workcalendar"
 )
 
 type PR struct {
 	*githubclient.PR
-	Author     Collaborator
-	Approvers  []Collaborator // Users who have approved the PR at least once
-	Commenters []Collaborator // Users who have commented on the PR but did not approve it
+	Author            Collaborator
+	Approvers         []Collaborator // Users whose latest review approves the current head of the PR
+	StaleApprovers    []Collaborator // Users whose latest review approves an older revision of the PR
+	ChangesRequesters []Collaborator // Users whose latest review requests changes
+	Commenters        []Collaborator // Users who have reviewed the PR without approving or requesting changes
+	// Users and teams whose review has been requested but who have not reviewed yet
+	RequestedReviewers []Collaborator
+	RequestedTeams     []Team
+	// Size label of the PR (e.g. "M"), empty if size badges are disabled or the size is unknown
+	SizeLabel string
+	// If true, the CI status of the PR is shown with an indicator
+	ShowCIStatus bool
+	// Defines from which point in time the age of the PR is measured
+	AgeBasis config.AgeBasis
+	// If set, the age of the PR is measured in working hours of the calendar
+	BusinessHoursCalendar *workcalendar.Calendar
 }
 
 type Collaborator struct {
@@ -28,8 +43,29 @@ func NewCollaborator(c *githubclient.Collaborator, slackUserId string) Collabora
 	}
 }
 
+type Team struct {
+	*githubclient.Team
+	SlackUserGroupID string // empty string if not available
+}
+
+func NewTeam(t *githubclient.Team, slackUserGroupId string) Team {
+	return Team{
+		Team:             t,
+		SlackUserGroupID: slackUserGroupId,
+	}
+}
+
+// Returns the age of the PR measured from the point in time defined by the age basis
+// (counting only working hours if a business hours calendar is set).
+func (pr PR) GetAge() time.Duration {
+	return pr.PR.GetAge(pr.AgeBasis, pr.BusinessHoursCalendar)
+}
+
 func (pr PR) GetPRAgeText() string {
-	duration := time.Since(pr.CreatedAt.Time)
+	duration := pr.GetAge()
+	if pr.BusinessHoursCalendar != nil {
+		return getWorkingAgeText(duration, pr.BusinessHoursCalendar.WorkdayLength())
+	}
 	if duration.Hours() >= 24 {
 		days := int(math.Round(duration.Hours())) / 24
 		return fmt.Sprintf("%d days ago", days)
@@ -42,20 +78,72 @@ func (pr PR) GetPRAgeText() string {
 	}
 }
 
-func ParsePRs(prs []githubclient.PR, slackUserIdByGitHubUsername map[string]string) []PR {
+// Working days are counted as multiples of the length of the working hours of a day.
+func getWorkingAgeText(duration time.Duration, workdayLength time.Duration) string {
+	if duration >= workdayLength {
+		days := int(math.Round(float64(duration) / float64(workdayLength)))
+		return fmt.Sprintf("%d working days ago", days)
+	} else if duration.Hours() >= 1 {
+		hours := int(math.Round(duration.Hours()))
+		return fmt.Sprintf("%d working hours ago", hours)
+	} else {
+		minutes := int(math.Round(duration.Minutes()))
+		return fmt.Sprintf("%d working minutes ago", minutes)
+	}
+}
+
+// Returns the number of approvals, including approvals of older revisions unless they
+// were configured to be ignored (in which case they are parsed as commenters).
+func (pr PR) GetApprovalCount() int {
+	return len(pr.Approvers) + len(pr.StaleApprovers)
+}
+
+func ParsePRs(
+	prs []githubclient.PR,
+	slackUserIdByGitHubUsername map[string]string,
+	slackUserGroupIdByGitHubTeam map[string]string,
+	contentInputs config.ContentInputs,
+) []PR {
 	var parsedPRs []PR
 	for _, pr := range prs {
-		parsedPRs = append(parsedPRs, parsePR(pr, slackUserIdByGitHubUsername))
+		parsedPRs = append(parsedPRs, parsePR(
+			pr, slackUserIdByGitHubUsername, slackUserGroupIdByGitHubTeam, contentInputs,
+		))
 	}
-	return sortPRsByCreatedAt(parsedPRs)
+	return sortPRsByAge(parsedPRs)
 }
 
-func parsePR(pr githubclient.PR, slackUserIdByGitHubUsername map[string]string) PR {
+func parsePR(
+	pr githubclient.PR,
+	slackUserIdByGitHubUsername map[string]string,
+	slackUserGroupIdByGitHubTeam map[string]string,
+	contentInputs config.ContentInputs,
+) PR {
+	staleApprovers := withSlackUserIds(pr.StaleApprovedByUsers, slackUserIdByGitHubUsername)
+	commenters := withSlackUserIds(pr.CommentedByUsers, slackUserIdByGitHubUsername)
+	if contentInputs.IgnoreStaleApprovals {
+		commenters = append(commenters, staleApprovers...)
+		staleApprovers = []Collaborator{}
+	}
+	sizeLabel := ""
+	if contentInputs.SizeBadgeThresholds != nil && pr.Size != nil {
+		sizeLabel = contentInputs.SizeBadgeThresholds.GetSizeLabel(pr.Size.LinesChanged())
+	}
 	return PR{
-		PR:         &pr,
-		Author:     NewCollaborator(&pr.Author, slackUserIdByGitHubUsername[pr.Author.Login]),
-		Approvers:  withSlackUserIds(pr.ApprovedByUsers, slackUserIdByGitHubUsername),
-		Commenters: withSlackUserIds(pr.CommentedByUsers, slackUserIdByGitHubUsername),
+		PR:                &pr,
+		Author:            NewCollaborator(&pr.Author, slackUserIdByGitHubUsername[pr.Author.Login]),
+		Approvers:         withSlackUserIds(pr.ApprovedByUsers, slackUserIdByGitHubUsername),
+		StaleApprovers:    staleApprovers,
+		ChangesRequesters: withSlackUserIds(pr.ChangesRequestedByUsers, slackUserIdByGitHubUsername),
+		Commenters:        commenters,
+		RequestedReviewers: withSlackUserIds(
+			pr.RequestedReviewers, slackUserIdByGitHubUsername,
+		),
+		RequestedTeams:        withSlackUserGroupIds(pr.RequestedTeams, slackUserGroupIdByGitHubTeam),
+		SizeLabel:             sizeLabel,
+		ShowCIStatus:          contentInputs.CIStatusIndicators,
+		AgeBasis:              contentInputs.AgeBasis,
+		BusinessHoursCalendar: contentInputs.BusinessHoursCalendar,
 	}
 }
 
@@ -70,10 +158,24 @@ func withSlackUserIds(
 	return result
 }
 
-func sortPRsByCreatedAt(prs []PR) []PR {
+// Teams are mapped to Slack user groups by their slugs.
+func withSlackUserGroupIds(
+	teams []githubclient.Team,
+	slackUserGroupIdByGitHubTeam map[string]string,
+) []Team {
+	result := make([]Team, len(teams))
+	for i, t := range teams {
+		result[i] = NewTeam(&t, slackUserGroupIdByGitHubTeam[t.Slug])
+	}
+	return result
+}
+
+// Sorts the PRs newest first by the point in time their age is measured from (see AgeBasis).
+func sortPRsByAge(prs []PR) []PR {
 	slices.SortStableFunc(prs, func(a, b PR) int {
-		if !a.GetCreatedAt().Time.Equal(b.GetCreatedAt().Time) {
-			return b.GetCreatedAt().Time.Compare(a.GetCreatedAt().Time)
+		aStart, bStart := a.GetAgeStartTime(a.AgeBasis), b.GetAgeStartTime(b.AgeBasis)
+		if !aStart.Equal(bStart) {
+			return bStart.Compare(aStart)
 		}
 		return b.GetUpdatedAt().Time.Compare(a.GetUpdatedAt().Time)
 	})
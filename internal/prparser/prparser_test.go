@@ -0,0 +1,146 @@
+package prparser_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/hellej/pr-slack-reminder-action/internal/apiclients/githubclient"
+	"github.com/hellej/pr-slack-reminder-action/internal/config"
+	"github.com/hellej/pr-slack-reminder-action/internal/prparser"
+	"github.com/hellej/pr-slack-reminder-action/internal/workcalendar"
+)
+
+func TestParsePRsStaleApprovals(t *testing.T) {
+	prs := []githubclient.PR{{
+		PullRequest:          &github.PullRequest{Number: github.Ptr(1)},
+		ApprovedByUsers:      []githubclient.Collaborator{{Login: "alice"}},
+		StaleApprovedByUsers: []githubclient.Collaborator{{Login: "bob"}},
+	}}
+	slackUserIdByGitHubUsername := map[string]string{"bob": "U2234567890"}
+
+	t.Run("stale approvals counted", func(t *testing.T) {
+		parsed := prparser.ParsePRs(prs, slackUserIdByGitHubUsername, nil, config.ContentInputs{})
+		if parsed[0].GetApprovalCount() != 2 {
+			t.Errorf("Expected 2 approvals, got %d", parsed[0].GetApprovalCount())
+		}
+		if len(parsed[0].StaleApprovers) != 1 || parsed[0].StaleApprovers[0].SlackUserID != "U2234567890" {
+			t.Errorf("Expected bob to be a stale approver with Slack user ID, got %v", parsed[0].StaleApprovers)
+		}
+	})
+
+	t.Run("stale approvals ignored", func(t *testing.T) {
+		parsed := prparser.ParsePRs(
+			prs, slackUserIdByGitHubUsername, nil, config.ContentInputs{IgnoreStaleApprovals: true},
+		)
+		if parsed[0].GetApprovalCount() != 1 {
+			t.Errorf("Expected 1 approval, got %d", parsed[0].GetApprovalCount())
+		}
+		if len(parsed[0].Commenters) != 1 || parsed[0].Commenters[0].Login != "bob" {
+			t.Errorf("Expected bob to be listed as a commenter, got %v", parsed[0].Commenters)
+		}
+	})
+}
+
+func TestParsePRsSizeLabel(t *testing.T) {
+	prs := []githubclient.PR{
+		{PullRequest: &github.PullRequest{Number: github.Ptr(1)}, Size: &githubclient.PRSize{Additions: 8, Deletions: 2}},
+		{PullRequest: &github.PullRequest{Number: github.Ptr(2)}, Size: &githubclient.PRSize{Additions: 300}},
+		{PullRequest: &github.PullRequest{Number: github.Ptr(3)}, Size: &githubclient.PRSize{Deletions: 5000}},
+		{PullRequest: &github.PullRequest{Number: github.Ptr(4)}}, // unknown size
+	}
+	thresholds := config.SizeThresholds{XS: 10, S: 100, M: 500, L: 1000}
+
+	testCases := []struct {
+		name           string
+		contentInputs  config.ContentInputs
+		expectedLabels map[int]string
+	}{
+		{"badges disabled", config.ContentInputs{}, map[int]string{1: "", 2: "", 3: "", 4: ""}},
+		{
+			"badges enabled",
+			config.ContentInputs{SizeBadgeThresholds: &thresholds},
+			map[int]string{1: "XS", 2: "M", 3: "XL", 4: ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, pr := range prparser.ParsePRs(prs, nil, nil, tc.contentInputs) {
+				if expected := tc.expectedLabels[pr.GetNumber()]; pr.SizeLabel != expected {
+					t.Errorf("Expected size label '%s' for PR #%d, got '%s'", expected, pr.GetNumber(), pr.SizeLabel)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePRsAgeBasis(t *testing.T) {
+	now := time.Now()
+	readyForReviewAt := now.Add(-3 * time.Hour)
+	reviewRequestedAt := now.Add(-2 * time.Hour)
+	prs := []githubclient.PR{
+		{
+			PullRequest: &github.PullRequest{
+				Number:    github.Ptr(1),
+				CreatedAt: &github.Timestamp{Time: now.Add(-72 * time.Hour)},
+				UpdatedAt: &github.Timestamp{Time: now.Add(-1 * time.Hour)},
+			},
+			ReadyForReviewAt:      &readyForReviewAt,
+			LastReviewRequestedAt: &reviewRequestedAt,
+		},
+		{ // opened as ready for review, no review requests
+			PullRequest: &github.PullRequest{
+				Number:    github.Ptr(2),
+				CreatedAt: &github.Timestamp{Time: now.Add(-5 * time.Hour)},
+				UpdatedAt: &github.Timestamp{Time: now.Add(-4 * time.Hour)},
+			},
+		},
+	}
+
+	testCases := []struct {
+		ageBasis        config.AgeBasis
+		expectedAgeText map[int]string
+		expectedOrder   []int // newest first
+	}{
+		{config.AgeBasisCreated, map[int]string{1: "3 days ago", 2: "5 hours ago"}, []int{2, 1}},
+		{config.AgeBasisReadyForReview, map[int]string{1: "3 hours ago", 2: "5 hours ago"}, []int{1, 2}},
+		{config.AgeBasisUpdated, map[int]string{1: "1 hours ago", 2: "4 hours ago"}, []int{1, 2}},
+		{config.AgeBasisReviewRequested, map[int]string{1: "2 hours ago", 2: "5 hours ago"}, []int{1, 2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.ageBasis), func(t *testing.T) {
+			parsed := prparser.ParsePRs(prs, nil, nil, config.ContentInputs{AgeBasis: tc.ageBasis})
+			order := []int{}
+			for _, pr := range parsed {
+				order = append(order, pr.GetNumber())
+			}
+			if !slices.Equal(order, tc.expectedOrder) {
+				t.Errorf("Expected PRs in order %v, got %v", tc.expectedOrder, order)
+			}
+			for _, pr := range parsed {
+				if expected := tc.expectedAgeText[pr.GetNumber()]; pr.GetPRAgeText() != expected {
+					t.Errorf("Expected age text '%s' for PR #%d, got '%s'", expected, pr.GetNumber(), pr.GetPRAgeText())
+				}
+			}
+		})
+	}
+}
+
+func TestParsePRsBusinessHoursAge(t *testing.T) {
+	calendar := workcalendar.NewDefaultCalendar()
+	prs := []githubclient.PR{{PullRequest: &github.PullRequest{
+		Number:    github.Ptr(1),
+		CreatedAt: &github.Timestamp{Time: time.Now().Add(-7 * 24 * time.Hour)},
+	}}}
+
+	parsed := prparser.ParsePRs(prs, nil, nil, config.ContentInputs{BusinessHoursCalendar: &calendar})
+	if parsed[0].GetAge().Round(time.Minute) != 40*time.Hour {
+		t.Errorf("Expected age of 40 working hours, got %v", parsed[0].GetAge())
+	}
+	if parsed[0].GetPRAgeText() != "5 working days ago" {
+		t.Errorf("Expected age text '5 working days ago', got '%s'", parsed[0].GetPRAgeText())
+	}
+}
@@ -0,0 +1,105 @@
+package workcalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+const iCalDateLayout = "20060102"
+
+// Reads the dates of the events of an iCalendar (.ics) file, e.g. a public holiday calendar.
+// Returns the dates in DateLayout format. Multi-day events (with DTEND as an exclusive end
+// date) produce one date per day. The dates are read as written in the file, i.e. the times
+// and timezones of events that are not all-day events are ignored. Recurrence rules (RRULE)
+// are not supported, so only the first occurrence of recurring events is read (with a warning).
+func ReadICalHolidays(r io.Reader) ([]string, error) {
+	holidays := []string{}
+	var start, end *time.Time
+	inEvent, recurring := false, false
+	lines, err := readUnfoldedICalLines(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read iCal data: %v", err)
+	}
+	for _, line := range lines {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		// Drop parameters like ";VALUE=DATE" from the property name
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent, recurring, start, end = true, false, nil, nil
+		case name == "END" && value == "VEVENT":
+			if start == nil {
+				return nil, fmt.Errorf("event without DTSTART in iCal data")
+			}
+			if recurring {
+				log.Printf(
+					"Recurrence rule (RRULE) of the iCal event on %s is not supported, using only its first occurrence as a holiday",
+					start.Format(DateLayout),
+				)
+			}
+			holidays = append(holidays, getEventDates(*start, end)...)
+			inEvent = false
+		case inEvent && name == "RRULE":
+			recurring = true
+		case inEvent && (name == "DTSTART" || name == "DTEND"):
+			date, err := parseICalDate(value)
+			if err != nil {
+				return nil, err
+			}
+			if name == "DTSTART" {
+				start = &date
+			} else {
+				end = &date
+			}
+		}
+	}
+	return holidays, nil
+}
+
+// Long lines are folded in iCal data by continuing them on the next line that starts
+// with a space or a tab.
+func readUnfoldedICalLines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return lines, scanner.Err()
+}
+
+func parseICalDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < len(iCalDateLayout) {
+		return time.Time{}, fmt.Errorf("invalid date in iCal data: %s", value)
+	}
+	date, err := time.Parse(iCalDateLayout, value[:len(iCalDateLayout)])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date in iCal data: %s", value)
+	}
+	return date, nil
+}
+
+// The end date of an all-day event is exclusive, so an event without an end date or with
+// an end date on the next day covers only the start date.
+func getEventDates(start time.Time, end *time.Time) []string {
+	dates := []string{start.Format(DateLayout)}
+	if end == nil {
+		return dates
+	}
+	for day := start.AddDate(0, 0, 1); day.Before(*end); day = day.AddDate(0, 0, 1) {
+		dates = append(dates, day.Format(DateLayout))
+	}
+	return dates
+}
@@ -0,0 +1,90 @@
+package workcalendar_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/workcalendar"
+)
+
+func TestReadICalHolidays(t *testing.T) {
+	testCases := []struct {
+		name          string
+		data          string
+		expected      []string
+		expectedError string
+	}{
+		{
+			name: "all-day events",
+			data: strings.Join([]string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20251224",
+				"DTEND;VALUE=DATE:20251227",
+				"SUMMARY:Christmas",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20260101",
+				"SUMMARY:New Year's Day",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			}, "\r\n"),
+			expected: []string{"2025-12-24", "2025-12-25", "2025-12-26", "2026-01-01"},
+		},
+		{
+			name: "folded lines and date-time values",
+			data: strings.Join([]string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VEVENT",
+				"SUMMARY:A holiday with a very long name that is folded",
+				"  on the next line",
+				"DTSTART;TZID=Europe/Helsinki:20250620T000000",
+				"DTEND;TZID=Europe/Helsinki:20250620T235900",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			}, "\n"),
+			expected: []string{"2025-06-20"},
+		},
+		{
+			name: "only the first occurrence of recurring events",
+			data: strings.Join([]string{
+				"BEGIN:VEVENT",
+				"DTSTART;VALUE=DATE:20251206",
+				"RRULE:FREQ=YEARLY",
+				"SUMMARY:Independence Day",
+				"END:VEVENT",
+			}, "\n"),
+			expected: []string{"2025-12-06"},
+		},
+		{
+			name:          "invalid date",
+			data:          "BEGIN:VEVENT\nDTSTART;VALUE=DATE:2025-12-24\nEND:VEVENT",
+			expectedError: "invalid date in iCal data: 2025-12-24",
+		},
+		{
+			name:          "event without start",
+			data:          "BEGIN:VEVENT\nSUMMARY:Holiday\nEND:VEVENT",
+			expectedError: "event without DTSTART in iCal data",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			holidays, err := workcalendar.ReadICalHolidays(strings.NewReader(tc.data))
+			if tc.expectedError != "" {
+				if err == nil || err.Error() != tc.expectedError {
+					t.Errorf("Expected error '%s', got: %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !reflect.DeepEqual(holidays, tc.expected) {
+				t.Errorf("Expected holidays %v, got %v", tc.expected, holidays)
+			}
+		})
+	}
+}
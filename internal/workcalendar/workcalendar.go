@@ -0,0 +1,92 @@
+// Package workcalendar defines working days and hours (in a timezone) and calculates how much
+// working time has passed between two points in time.
+package workcalendar
+
+import (
+	"slices"
+	"time"
+	_ "time/tzdata" // embedded timezone database in case the runner does not have one
+)
+
+const DateLayout = "2006-01-02"
+
+type Calendar struct {
+	Location    *time.Location
+	WorkingDays []time.Weekday
+	// Start and end of the working hours as offsets from midnight (e.g. 9h and 17h)
+	WorkdayStart time.Duration
+	WorkdayEnd   time.Duration
+	// Dates (in DateLayout format) that are not working days even if their weekday is
+	Holidays []string
+}
+
+// Returns a calendar with working hours from 9 to 17 on weekdays (in UTC) and no holidays.
+func NewDefaultCalendar() Calendar {
+	return Calendar{
+		Location: time.UTC,
+		WorkingDays: []time.Weekday{
+			time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+		},
+		WorkdayStart: 9 * time.Hour,
+		WorkdayEnd:   17 * time.Hour,
+		Holidays:     []string{},
+	}
+}
+
+// Returns the length of the working hours of a single working day.
+func (c Calendar) WorkdayLength() time.Duration {
+	return c.WorkdayEnd - c.WorkdayStart
+}
+
+// Returns true if the date of t (in the timezone of the calendar) is a working day
+// and not a holiday.
+func (c Calendar) IsWorkingDay(t time.Time) bool {
+	local := t.In(c.Location)
+	return slices.Contains(c.WorkingDays, local.Weekday()) &&
+		!slices.Contains(c.Holidays, local.Format(DateLayout))
+}
+
+// Returns the amount of working time between start and end, i.e. the time that falls within
+// the working hours of working days. Returns 0 if end is before start.
+func (c Calendar) WorkingTimeBetween(start time.Time, end time.Time) time.Duration {
+	if !end.After(start) {
+		return 0
+	}
+	var total time.Duration
+	localStart := start.In(c.Location)
+	day := time.Date(localStart.Year(), localStart.Month(), localStart.Day(), 0, 0, 0, 0, c.Location)
+	for day.Before(end) {
+		if c.IsWorkingDay(day) {
+			workStart := latestOf(c.atTimeOfDay(day, c.WorkdayStart), start)
+			workEnd := earliestOf(c.atTimeOfDay(day, c.WorkdayEnd), end)
+			if workEnd.After(workStart) {
+				total += workEnd.Sub(workStart)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return total
+}
+
+// Returns the time of day (an offset from midnight like WorkdayStart) on the date of day in
+// the timezone of the calendar. Unlike adding the offset to midnight, this is not shifted on
+// days when daylight saving time starts or ends.
+func (c Calendar) atTimeOfDay(day time.Time, offset time.Duration) time.Time {
+	year, month, date := day.Date()
+	hours, minutes := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	return time.Date(year, month, date, hours, minutes, 0, 0, c.Location)
+}
+
+func latestOf(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliestOf(a time.Time, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,83 @@
+package workcalendar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hellej/pr-slack-reminder-action/internal/workcalendar"
+)
+
+func TestIsWorkingDay(t *testing.T) {
+	calendar := workcalendar.NewDefaultCalendar()
+	calendar.Holidays = []string{"2025-12-24"}
+
+	testCases := []struct {
+		name     string
+		time     time.Time
+		expected bool
+	}{
+		{"weekday", time.Date(2025, 12, 22, 12, 0, 0, 0, time.UTC), true},
+		{"saturday", time.Date(2025, 12, 20, 12, 0, 0, 0, time.UTC), false},
+		{"holiday", time.Date(2025, 12, 24, 12, 0, 0, 0, time.UTC), false},
+		{"sunday in timezone of calendar", time.Date(2025, 12, 22, 1, 0, 0, 0, time.FixedZone("UTC+3", 3*3600)), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := calendar.IsWorkingDay(tc.time); actual != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWorkingTimeBetween(t *testing.T) {
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatalf("Unable to load location: %v", err)
+	}
+	calendar := workcalendar.NewDefaultCalendar()
+	calendar.Holidays = []string{"2025-12-24"}
+	helsinkiCalendar := workcalendar.NewDefaultCalendar()
+	helsinkiCalendar.Location = helsinki
+	// Daylight saving time starts in Helsinki on Sunday 2025-03-30 (UTC+2 -> UTC+3)
+	dstCalendar := helsinkiCalendar
+	dstCalendar.WorkingDays = []time.Weekday{time.Sunday}
+	dstStartDay := func(hour int) time.Time {
+		return time.Date(2025, 3, 30, hour, 0, 0, 0, time.UTC)
+	}
+
+	// 2025-12-19 is a Friday
+	at := func(day int, hour int) time.Time {
+		return time.Date(2025, 12, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		calendar workcalendar.Calendar
+		start    time.Time
+		end      time.Time
+		expected time.Duration
+	}{
+		{"within a working day", calendar, at(19, 10), at(19, 12), 2 * time.Hour},
+		{"outside working hours", calendar, at(19, 18), at(19, 23), 0},
+		{"friday evening to monday morning", calendar, at(19, 18), at(22, 10), 1 * time.Hour},
+		{"friday afternoon to monday afternoon", calendar, at(19, 15), at(22, 14), 7 * time.Hour},
+		{"full week", calendar, at(15, 12), at(22, 12), 40 * time.Hour},
+		{"holiday skipped", calendar, at(23, 9), at(25, 17), 16 * time.Hour},
+		{"end before start", calendar, at(22, 12), at(19, 12), 0},
+		// Working hours in Helsinki (UTC+2) are 7-15 UTC
+		{"timezone of calendar", helsinkiCalendar, at(19, 6), at(19, 16), 8 * time.Hour},
+		// Working hours in Helsinki (UTC+3) are 6-14 UTC on the day daylight saving time starts
+		{"daylight saving time start", dstCalendar, dstStartDay(6), dstStartDay(7), time.Hour},
+		{"daylight saving time start full day", dstCalendar, dstStartDay(0), dstStartDay(20), 8 * time.Hour},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.calendar.WorkingTimeBetween(tc.start, tc.end); actual != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
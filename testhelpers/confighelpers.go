@@ -74,12 +74,34 @@ func setEnvFromConfig(t *testing.T, c TestConfig, overrides *map[string]any) {
 	setInputEnv(t, overrides, config.InputSlackChannelName, c.SlackChannelName)
 	setInputEnv(t, overrides, config.InputSlackChannelID, c.SlackChannelID)
 	setInputEnv(t, overrides, config.InputSlackUserIdByGitHubUsername, c.SlackUserIdByGitHubUsername)
+	setInputEnv(t, overrides, config.InputSlackUserGroupIdByGitHubTeam, c.SlackUserGroupIdByGitHubTeam)
 	setInputEnv(t, overrides, config.InputNoPRsMessage, c.ContentInputs.NoPRsMessage)
 	setInputEnv(t, overrides, config.InputMainListHeading, c.ContentInputs.MainListHeading)
 	setInputEnv(t, overrides, config.InputOldPRsListHeading, c.ContentInputs.OldPRsListHeading)
 	setInputEnv(t, overrides, config.InputOldPRThresholdHours, c.ContentInputs.OldPRThresholdHours)
+	setInputEnv(t, overrides, config.InputAgeCategories, "") // not set unless overridden
+	setInputEnv(t, overrides, config.InputAgeBasis, string(c.ContentInputs.AgeBasis))
+	setInputEnv(t, overrides, config.InputBusinessHoursAge, c.ContentInputs.BusinessHoursCalendar != nil)
+	setInputEnv(t, overrides, config.InputWorkingCalendar, "")  // defaults unless overridden
+	setInputEnv(t, overrides, config.InputHolidaysICalPath, "") // not set unless overridden
+	setInputEnv(t, overrides, config.InputSkipNonWorkingDays, c.SkipNonWorkingDays)
+	setInputEnv(t, overrides, config.InputDraftPRsListHeading, c.ContentInputs.DraftPRsListHeading)
+	setInputEnv(t, overrides, config.InputReadyToMergeListHeading, c.ContentInputs.ReadyToMergeListHeading)
+	setInputEnv(t, overrides, config.InputReadyToMergeApprovals, nil) // defaults unless overridden
+	setInputEnv(t, overrides, config.InputNeedsRebaseListHeading, c.ContentInputs.NeedsRebaseListHeading)
+	setInputEnv(t, overrides, config.InputIgnoreStaleApprovals, c.ContentInputs.IgnoreStaleApprovals)
+	setInputEnv(t, overrides, config.InputReviewerDisplay, string(c.ContentInputs.ReviewerDisplay))
+	setInputEnv(t, overrides, config.InputGroupBy, string(c.ContentInputs.GroupBy))
+	setInputEnv(t, overrides, config.InputSortBy, string(c.ContentInputs.SortBy))
+	setInputEnv(t, overrides, config.InputLabelGroups, "") // not set unless overridden
+	setInputEnv(t, overrides, config.InputUngroupedHeading, c.ContentInputs.UngroupedHeading)
+	setInputEnv(t, overrides, config.InputCIStatusIndicators, c.ContentInputs.CIStatusIndicators)
+	setInputEnv(t, overrides, config.InputSizeBadges, c.ContentInputs.SizeBadgeThresholds != nil)
+	setInputEnv(t, overrides, config.InputSizeBadgeThresholds, "") // defaults unless overridden
 	setInputEnv(t, overrides, config.InputGlobalFilters, c.GlobalFiltersRaw)
 	setInputEnv(t, overrides, config.InputRepositoryFilters, c.RepositoryFiltersRaw)
+	setInputEnv(t, overrides, config.InputFiltersMergeStrategy, string(c.FetchInputs.FiltersMergeStrategy))
+	setInputEnv(t, overrides, config.InputContinueOnRepositoryErrors, c.FetchInputs.ContinueOnRepositoryErrors)
 }
 
 func setInputEnv(t *testing.T, overrides *map[string]interface{}, inputName string, value any) {
@@ -109,6 +131,8 @@ func setInputEnv(t *testing.T, overrides *map[string]interface{}, inputName stri
 		strValue = listAsString(v)
 	case int:
 		strValue = strconv.Itoa(v)
+	case bool:
+		strValue = strconv.FormatBool(v)
 	case *int:
 		if v == nil {
 			t.Setenv(envName, "")
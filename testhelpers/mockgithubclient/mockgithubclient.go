@@ -2,6 +2,7 @@ package mockgithubclient
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/google/go-github/v72/github"
@@ -13,9 +14,13 @@ func MakeMockGitHubClientGetter(
 	prsByRepo map[string][]*github.PullRequest,
 	listPRsResponseStatus int,
 	listPRsErr error,
+	listPRsErrByRepo map[string]error,
 	reviewsByPRNumber map[int][]*github.PullRequestReview,
+	checkRunsByHeadSHA map[string][]*github.CheckRun,
+	timelineEventsByPRNumber map[int][]*github.Timeline,
 ) func(token string) githubclient.Client {
 	return func(token string) githubclient.Client {
+		ciService := &mockCIService{mockCheckRunsByRef: checkRunsByHeadSHA}
 		return githubclient.NewClient(&mockPullRequestsService{
 			mockPRs:       prs,
 			mockPRsByRepo: prsByRepo,
@@ -26,13 +31,17 @@ func MakeMockGitHubClientGetter(
 			},
 			mockReviewsByPRNumber: reviewsByPRNumber,
 			mockError:             listPRsErr,
-		})
+			mockErrorsByRepo:      listPRsErrByRepo,
+		}, ciService, ciService, &mockIssuesService{mockTimelineEventsByPRNumber: timelineEventsByPRNumber})
 	}
 }
 
 func (m *mockPullRequestsService) List(
 	ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions,
 ) ([]*github.PullRequest, *github.Response, error) {
+	if err, ok := m.mockErrorsByRepo[repo]; ok {
+		return nil, &github.Response{Response: &http.Response{StatusCode: 404}}, err
+	}
 	if m.mockPRsByRepo != nil {
 		return m.mockPRsByRepo[repo], m.mockResponse, m.mockError
 	}
@@ -52,6 +61,57 @@ type mockPullRequestsService struct {
 	mockReviewsByPRNumber map[int][]*github.PullRequestReview
 	mockResponse          *github.Response
 	mockError             error
+	mockErrorsByRepo      map[string]error // errors (with status 404) by repository name
 	mockReviewsResponse   *github.Response
 	mockReviewsError      error
 }
+
+func (m *mockPullRequestsService) Get(
+	ctx context.Context, owner string, repo string, number int,
+) (*github.PullRequest, *github.Response, error) {
+	prs := m.mockPRs
+	if m.mockPRsByRepo != nil {
+		prs = m.mockPRsByRepo[repo]
+	}
+	for _, pr := range prs {
+		if pr.GetNumber() == number {
+			details := *pr
+			if details.Mergeable == nil {
+				details.Mergeable = github.Ptr(true) // as if GitHub had computed the mergeability
+			}
+			return &details, &github.Response{Response: &http.Response{StatusCode: 200}}, nil
+		}
+	}
+	return nil, &github.Response{Response: &http.Response{StatusCode: 404}}, errors.New("not found")
+}
+
+// Serves check runs by commit SHA (commit statuses are not used in the tests).
+type mockCIService struct {
+	mockCheckRunsByRef map[string][]*github.CheckRun
+}
+
+func (m *mockCIService) GetCombinedStatus(
+	ctx context.Context, owner string, repo string, ref string, opts *github.ListOptions,
+) (*github.CombinedStatus, *github.Response, error) {
+	return &github.CombinedStatus{TotalCount: github.Ptr(0)}, &github.Response{Response: &http.Response{StatusCode: 200}}, nil
+}
+
+func (m *mockCIService) ListCheckRunsForRef(
+	ctx context.Context, owner string, repo string, ref string, opts *github.ListCheckRunsOptions,
+) (*github.ListCheckRunsResults, *github.Response, error) {
+	checkRuns := m.mockCheckRunsByRef[ref]
+	return &github.ListCheckRunsResults{
+		Total:     github.Ptr(len(checkRuns)),
+		CheckRuns: checkRuns,
+	}, &github.Response{Response: &http.Response{StatusCode: 200}}, nil
+}
+
+type mockIssuesService struct {
+	mockTimelineEventsByPRNumber map[int][]*github.Timeline
+}
+
+func (m *mockIssuesService) ListIssueTimeline(
+	ctx context.Context, owner string, repo string, number int, opts *github.ListOptions,
+) ([]*github.Timeline, *github.Response, error) {
+	return m.mockTimelineEventsByPRNumber[number], &github.Response{Response: &http.Response{StatusCode: 200}}, nil
+}
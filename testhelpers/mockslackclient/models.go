@@ -76,6 +76,28 @@ func (b BlocksWrapper) ContainsHeading(heading string) bool {
 	return false
 }
 
+// Returns the headings of the PR lists in the order they appear in the message.
+func (b BlocksWrapper) GetHeadings() []string {
+	headings := []string{}
+	for _, item := range b.GetPRLists() {
+		if !slices.Contains(headings, item.Heading) {
+			headings = append(headings, item.Heading)
+		}
+	}
+	return headings
+}
+
+// Returns the texts of the PR list items under the given heading (from all lists under it).
+func (b BlocksWrapper) GetPRListItems(heading string) []string {
+	var items []string
+	for _, item := range b.GetPRLists() {
+		if item.Heading == heading {
+			items = append(items, item.PRListItems...)
+		}
+	}
+	return items
+}
+
 func (b BlocksWrapper) GetPRCount() int {
 	var count int
 	for _, item := range b.GetPRLists() {
@@ -84,6 +106,32 @@ func (b BlocksWrapper) GetPRCount() int {
 	return count
 }
 
+// Checks if any context block (e.g. a footer) contains the given text.
+func (b BlocksWrapper) ContainsContextText(text string) bool {
+	for _, block := range b.Blocks {
+		if block.Type != "context" || block.Elements == nil {
+			continue
+		}
+		var textObjects []TextObject
+		if err := json.Unmarshal(block.Elements, &textObjects); err != nil {
+			panic(fmt.Sprintf("Unexpected context block elements: %v", err))
+		}
+		if slices.ContainsFunc(textObjects, func(t TextObject) bool {
+			return strings.Contains(t.Text, text)
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if any section block (e.g. a mention at the top of a PR list) contains the given text.
+func (b BlocksWrapper) ContainsSectionText(text string) bool {
+	return slices.ContainsFunc(b.Blocks, func(block Block) bool {
+		return block.Type == "section" && block.Text != nil && strings.Contains(block.Text.Text, text)
+	})
+}
+
 type Block struct {
 	Type     string          `json:"type"`
 	Text     *TextObject     `json:"text,omitempty"`